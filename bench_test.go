@@ -2,6 +2,7 @@ package goweb_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -25,6 +26,10 @@ var echoApp *echo.Echo
 var gorillaApp *mux.Router
 var martiniApp *martini.ClassicMartini
 
+// manyRoutes is the number of routes registered by each
+// app in the ManyRoutes benchmarks.
+const manyRoutes = 300
+
 type Post struct {
 	ID    int    `json:"id"`
 	Title string `json:"title"`
@@ -119,6 +124,24 @@ func init() {
 			"baz": params["baz"],
 		}
 	})
+
+	for i := 0; i < manyRoutes; i++ {
+		gowebApp.GET(fmt.Sprintf("/many%d/{id}", i), func(c *goweb.Context) goweb.Responder {
+			return c.Text(http.StatusOK, c.Param("id"))
+		})
+		ginApp.GET(fmt.Sprintf("/many%d/:id", i), func(c *gin.Context) {
+			c.String(http.StatusOK, c.Param("id"))
+		})
+		gorillaApp.HandleFunc(fmt.Sprintf("/many%d/{id}", i), func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(mux.Vars(r)["id"]))
+		}).Methods("GET")
+		echoApp.GET(fmt.Sprintf("/many%d/:id", i), func(c echo.Context) error {
+			return c.String(http.StatusOK, c.Param("id"))
+		})
+		martiniApp.Get(fmt.Sprintf("/many%d/:id", i), func(params martini.Params) string {
+			return params["id"]
+		})
+	}
 }
 
 type Fatalfer interface {
@@ -295,3 +318,122 @@ func BenchmarkMartiniPathParams(b *testing.B) {
 		equals(b, rr.Code, http.StatusOK)
 	}
 }
+
+func BenchmarkGowebManyRoutes(b *testing.B) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("/many%d/1234", manyRoutes-1), nil)
+	equals(b, err, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rr := httptest.NewRecorder()
+		gowebApp.ServeHTTP(rr, req)
+		equals(b, rr.Code, http.StatusOK)
+	}
+}
+
+func BenchmarkGinManyRoutes(b *testing.B) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("/many%d/1234", manyRoutes-1), nil)
+	equals(b, err, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rr := httptest.NewRecorder()
+		ginApp.ServeHTTP(rr, req)
+		equals(b, rr.Code, http.StatusOK)
+	}
+}
+
+func BenchmarkGorillaManyRoutes(b *testing.B) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("/many%d/1234", manyRoutes-1), nil)
+	equals(b, err, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rr := httptest.NewRecorder()
+		gorillaApp.ServeHTTP(rr, req)
+		equals(b, rr.Code, http.StatusOK)
+	}
+}
+
+func BenchmarkEchoManyRoutes(b *testing.B) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("/many%d/1234", manyRoutes-1), nil)
+	equals(b, err, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rr := httptest.NewRecorder()
+		echoApp.ServeHTTP(rr, req)
+		equals(b, rr.Code, http.StatusOK)
+	}
+}
+
+func BenchmarkMartiniManyRoutes(b *testing.B) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("/many%d/1234", manyRoutes-1), nil)
+	equals(b, err, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rr := httptest.NewRecorder()
+		martiniApp.ServeHTTP(rr, req)
+		equals(b, rr.Code, http.StatusOK)
+	}
+}
+
+// discardResponseWriter is reused across iterations, so
+// that the Dispatch benchmarks only count the allocations
+// made by the router.
+type discardResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func newDiscardResponseWriter() *discardResponseWriter {
+	return &discardResponseWriter{header: make(http.Header)}
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteString(s string) (int, error) {
+	return len(s), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func benchmarkDispatch(b *testing.B, app http.Handler, path string) {
+	req, err := http.NewRequest("GET", path, nil)
+	equals(b, err, nil)
+	w := newDiscardResponseWriter()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app.ServeHTTP(w, req)
+	}
+	equals(b, w.status, http.StatusOK)
+}
+
+func BenchmarkGowebStaticDispatch(b *testing.B) {
+	benchmarkDispatch(b, gowebApp, "/plaintext")
+}
+
+func BenchmarkGinStaticDispatch(b *testing.B) {
+	benchmarkDispatch(b, ginApp, "/plaintext")
+}
+
+func BenchmarkEchoStaticDispatch(b *testing.B) {
+	benchmarkDispatch(b, echoApp, "/plaintext")
+}
+
+func BenchmarkGowebParamDispatch(b *testing.B) {
+	benchmarkDispatch(b, gowebApp, fmt.Sprintf("/many%d/1234", manyRoutes-1))
+}
+
+func BenchmarkGinParamDispatch(b *testing.B) {
+	benchmarkDispatch(b, ginApp, fmt.Sprintf("/many%d/1234", manyRoutes-1))
+}
+
+func BenchmarkEchoParamDispatch(b *testing.B) {
+	benchmarkDispatch(b, echoApp, fmt.Sprintf("/many%d/1234", manyRoutes-1))
+}
@@ -0,0 +1,347 @@
+package goweb
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxMemory is the number of bytes of a multipart
+// form kept in memory, as in net/http.
+const defaultMaxMemory = 32 << 20
+
+// ErrUnsupportedMediaType is the error of a BindError
+// returned by Context.Bind for a request body whose
+// Content-Type has no decoder.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// BindError is returned by Context.Bind if a value can't be
+// decoded into the target. Source is "body", "form",
+// "query", "header", "path" or, for an invalid default tag,
+// "default". Field is the name of the struct field, and is
+// empty if the error isn't caused by one field.
+type BindError struct {
+	Field  string
+	Source string
+	Err    error
+}
+
+func (e *BindError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("bind %s: %v", e.Source, e.Err)
+	}
+	return fmt.Sprintf("bind %s %s: %v", e.Source, e.Field, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// Bind decodes the request into the struct pointed to by
+// dst. Fields are first set to the values of their default
+// tags. The body is then decoded according to its
+// Content-Type: JSON and XML with the encoding/json and
+// encoding/xml packages, and url-encoded and multipart forms
+// into fields with a form tag. Finally fields with a query,
+// header or path tag are set from the request's query
+// values, headers and path params, in that order:
+//
+//	type Input struct {
+//		ID     int      `path:"id"`
+//		Page   int      `query:"page" default:"1"`
+//		Tenant string   `header:"X-Tenant"`
+//		Name   string   `json:"name" form:"name"`
+//		Tags   []string `query:"tag"`
+//	}
+//
+// Tagged fields may be strings, numbers, bools, durations,
+// times (RFC 3339 or a date), types implementing
+// encoding.TextUnmarshaler, pointers to or slices of these,
+// and, for multipart forms, *multipart.FileHeader or a slice
+// of them. Fields of embedded structs are bound too. A
+// *BindError is returned if a value can't be decoded. The
+// bound struct is then checked with Context.ValidateStruct,
+// and a *ValidationError is returned if it is invalid.
+func (c *Context) Bind(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: target must be a non-nil pointer to a struct, not %T", dst)
+	}
+	v = v.Elem()
+	fields := bindFieldsOf(v.Type())
+	for _, f := range fields {
+		if !f.hasDefault {
+			continue
+		}
+		if err := setField(v.FieldByIndex(f.index), []string{f.def}); err != nil {
+			return &BindError{Field: f.name, Source: "default", Err: err}
+		}
+	}
+	if err := c.bindBody(dst, v, fields); err != nil {
+		return err
+	}
+	query := c.Request.URL.Query()
+	for _, f := range fields {
+		for source := sourceQuery; source <= sourcePath; source++ {
+			key := f.keys[source]
+			if key == "" {
+				continue
+			}
+			var values []string
+			switch source {
+			case sourceQuery:
+				values = query[key]
+			case sourceHeader:
+				values = c.Request.Header.Values(key)
+			case sourcePath:
+				if value, ok := c.params.lookup(key); ok {
+					values = []string{value}
+				}
+			}
+			if len(values) == 0 {
+				continue
+			}
+			if err := setField(v.FieldByIndex(f.index), values); err != nil {
+				return &BindError{Field: f.name, Source: bindSources[source], Err: err}
+			}
+		}
+	}
+	return c.ValidateStruct(dst)
+}
+
+// bindBody decodes the request body into dst.
+func (c *Context) bindBody(dst interface{}, v reflect.Value, fields []bindField) error {
+	r := c.Request
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+	ct := r.Header.Get(contentTypeHeader)
+	if ct == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return &BindError{Source: "body", Err: err}
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		err := json.NewDecoder(r.Body).Decode(dst)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var te *json.UnmarshalTypeError
+			if errors.As(err, &te) {
+				return &BindError{Field: te.Field, Source: "body", Err: err}
+			}
+			return &BindError{Source: "body", Err: err}
+		}
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		err := xml.NewDecoder(r.Body).Decode(dst)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return &BindError{Source: "body", Err: err}
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return &BindError{Source: "body", Err: err}
+		}
+		return bindForm(v, fields, r.PostForm, nil)
+	case mediaType == "multipart/form-data":
+		if err := r.ParseMultipartForm(defaultMaxMemory); err != nil {
+			return &BindError{Source: "body", Err: err}
+		}
+		return bindForm(v, fields, r.MultipartForm.Value, r.MultipartForm.File)
+	default:
+		return &BindError{Source: "body", Err: ErrUnsupportedMediaType}
+	}
+	return nil
+}
+
+// bindForm sets the fields with a form tag from the form's
+// values and files.
+func bindForm(v reflect.Value, fields []bindField, values map[string][]string, files map[string][]*multipart.FileHeader) error {
+	for _, f := range fields {
+		key := f.keys[sourceForm]
+		if key == "" {
+			continue
+		}
+		fv := v.FieldByIndex(f.index)
+		var err error
+		switch fv.Type() {
+		case fileHeaderType:
+			if fhs := files[key]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs[0]))
+			}
+		case fileHeadersType:
+			if fhs := files[key]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs))
+			}
+		default:
+			if vs := values[key]; len(vs) > 0 {
+				err = setField(fv, vs)
+			}
+		}
+		if err != nil {
+			return &BindError{Field: f.name, Source: "form", Err: err}
+		}
+	}
+	return nil
+}
+
+// Sources of tagged fields, in the order of bindSources.
+const (
+	sourceForm = iota
+	sourceQuery
+	sourceHeader
+	sourcePath
+)
+
+var bindSources = [...]string{"form", "query", "header", "path"}
+
+type bindField struct {
+	index      []int
+	name       string
+	keys       [len(bindSources)]string
+	def        string
+	hasDefault bool
+}
+
+var bindFieldCache sync.Map
+
+// bindFieldsOf returns the fields of the struct type with a
+// source or default tag, including those of embedded
+// structs.
+func bindFieldsOf(t reflect.Type) []bindField {
+	if fields, ok := bindFieldCache.Load(t); ok {
+		return fields.([]bindField)
+	}
+	fields := appendBindFields(nil, t, nil)
+	bindFieldCache.Store(t, fields)
+	return fields
+}
+
+func appendBindFields(fields []bindField, t reflect.Type, index []int) []bindField {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			fields = appendBindFields(fields, sf.Type, fieldIndex)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		f := bindField{index: fieldIndex, name: sf.Name}
+		tagged := false
+		for source, name := range bindSources {
+			f.keys[source] = sf.Tag.Get(name)
+			tagged = tagged || f.keys[source] != ""
+		}
+		f.def, f.hasDefault = sf.Tag.Lookup("default")
+		if tagged || f.hasDefault {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType     = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// setField sets the field to the given values. Slices are
+// set to all values, and other types, including slices
+// implementing encoding.TextUnmarshaler, to the first.
+func setField(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Slice && !v.Addr().Type().Implements(textUnmarshalerType) {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(s.Index(i), value); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return setValue(v, values[0])
+}
+
+// setValue parses s into v.
+func setValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type().Elem())
+		if err := setValue(p.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	switch v.Type() {
+	case timeType:
+		t, err := parseTime(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return unwrapNumError(err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return unwrapNumError(err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return unwrapNumError(err)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return unwrapNumError(err)
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
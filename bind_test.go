@@ -0,0 +1,183 @@
+package goweb_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twharmon/goweb"
+)
+
+type bindPage struct {
+	Page  int `query:"page" default:"1"`
+	Limit int `query:"limit" default:"20"`
+}
+
+type bindInput struct {
+	bindPage
+	ID      goweb.UUID    `path:"id"`
+	Tenant  string        `header:"X-Tenant"`
+	Name    string        `json:"name" xml:"name" form:"name"`
+	Age     *int          `json:"age" xml:"age" form:"age"`
+	Tags    []string      `query:"tag"`
+	Since   time.Time     `query:"since"`
+	Timeout time.Duration `query:"timeout" default:"5s"`
+	Admin   bool          `header:"X-Admin"`
+}
+
+func (in *bindInput) String() string {
+	age := "nil"
+	if in.Age != nil {
+		age = fmt.Sprint(*in.Age)
+	}
+	return fmt.Sprintf("%s %s %s %s %v %d %d %s %s %v", in.ID, in.Tenant, in.Name, age, in.Tags, in.Page, in.Limit,
+		in.Since.Format("2006-01-02"), in.Timeout, in.Admin)
+}
+
+func bindHandler(c *goweb.Context) goweb.Responder {
+	var in bindInput
+	if err := c.Bind(&in); err != nil {
+		return c.Text(http.StatusBadRequest, err.Error())
+	}
+	return c.Text(http.StatusOK, in.String())
+}
+
+const bindID = "123e4567-e89b-12d3-a456-426614174000"
+
+func withContentType(ct string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set("Content-Type", ct)
+		r.Header.Set("X-Tenant", "acme")
+	}
+}
+
+func TestBindJSON(t *testing.T) {
+	app := goweb.New()
+	app.POST("/users/{id}", bindHandler)
+	body := strings.NewReader(`{"name":"gopher","age":12}`)
+	assert(t, app, "POST", "/users/"+bindID+"?tag=a&tag=b&page=3&since=2022-05-01", body, withContentType("application/json; charset=utf-8"),
+		http.StatusOK, bindID+" acme gopher 12 [a b] 3 20 2022-05-01 5s false")
+}
+
+func TestBindVendorJSON(t *testing.T) {
+	app := goweb.New()
+	app.POST("/users/{id}", bindHandler)
+	body := strings.NewReader(`{"name":"gopher"}`)
+	assert(t, app, "POST", "/users/"+bindID, body, withContentType("application/vnd.acme.v2+json"),
+		http.StatusOK, bindID+" acme gopher nil [] 1 20 0001-01-01 5s false")
+}
+
+func TestBindXML(t *testing.T) {
+	app := goweb.New()
+	app.POST("/users/{id}", bindHandler)
+	body := strings.NewReader(`<user><name>gopher</name><age>12</age></user>`)
+	assert(t, app, "POST", "/users/"+bindID+"?timeout=1m", body, withContentType("application/xml"),
+		http.StatusOK, bindID+" acme gopher 12 [] 1 20 0001-01-01 1m0s false")
+}
+
+func TestBindForm(t *testing.T) {
+	app := goweb.New()
+	app.POST("/users/{id}", bindHandler)
+	body := strings.NewReader("name=gopher&age=12")
+	assert(t, app, "POST", "/users/"+bindID+"?limit=5", body, func(r *http.Request) {
+		withContentType("application/x-www-form-urlencoded")(r)
+		r.Header.Set("X-Admin", "true")
+	}, http.StatusOK, bindID+" acme gopher 12 [] 1 5 0001-01-01 5s true")
+}
+
+func TestBindMultipart(t *testing.T) {
+	type upload struct {
+		Name  string                  `form:"name"`
+		File  *multipart.FileHeader   `form:"file"`
+		Files []*multipart.FileHeader `form:"files"`
+	}
+	app := goweb.New()
+	app.POST("/", func(c *goweb.Context) goweb.Responder {
+		var in upload
+		if err := c.Bind(&in); err != nil {
+			return c.Text(http.StatusBadRequest, err.Error())
+		}
+		f, err := in.File.Open()
+		if err != nil {
+			return c.Text(http.StatusInternalServerError, err.Error())
+		}
+		defer f.Close()
+		content, _ := io.ReadAll(f)
+		return c.Text(http.StatusOK, fmt.Sprintf("%s %s %s %d", in.Name, in.File.Filename, content, len(in.Files)))
+	})
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("name", "gopher")
+	fw, _ := w.CreateFormFile("file", "a.txt")
+	fw.Write([]byte("hello"))
+	w.CreateFormFile("files", "b.txt")
+	w.CreateFormFile("files", "c.txt")
+	w.Close()
+	assert(t, app, "POST", "/", &buf, withContentType(w.FormDataContentType()), http.StatusOK, "gopher a.txt hello 2")
+}
+
+func TestBindTextUnmarshalerSlice(t *testing.T) {
+	type input struct {
+		IP  net.IP   `query:"ip"`
+		IPs []net.IP `query:"ips"`
+	}
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		var in input
+		if err := c.Bind(&in); err != nil {
+			return c.Text(http.StatusBadRequest, err.Error())
+		}
+		return c.Text(http.StatusOK, fmt.Sprint(in.IP, in.IPs))
+	})
+	assert(t, app, "GET", "/?ip=1.2.3.4&ips=::1&ips=10.0.0.1", nil, nil, http.StatusOK, "1.2.3.4 [::1 10.0.0.1]")
+	assert(t, app, "GET", "/?ip=x", nil, nil, http.StatusBadRequest, "bind query IP: invalid IP address: x")
+}
+
+func TestBindErrors(t *testing.T) {
+	app := goweb.New()
+	app.POST("/users/{id}", bindHandler)
+	assert(t, app, "POST", "/users/abc", nil, nil, http.StatusBadRequest, "bind path ID: invalid UUID")
+	assert(t, app, "POST", "/users/"+bindID+"?page=x", nil, nil, http.StatusBadRequest, "bind query Page: invalid syntax")
+	assert(t, app, "POST", "/users/"+bindID, nil, func(r *http.Request) {
+		r.Header.Set("X-Admin", "maybe")
+	}, http.StatusBadRequest, "bind header Admin: invalid syntax")
+	assert(t, app, "POST", "/users/"+bindID, strings.NewReader("age=x"), withContentType("application/x-www-form-urlencoded"),
+		http.StatusBadRequest, "bind form Age: invalid syntax")
+	assert(t, app, "POST", "/users/"+bindID, strings.NewReader(`{"age":"x"}`), withContentType("application/json"),
+		http.StatusBadRequest, "bind body age: json: cannot unmarshal string into Go struct field bindInput.age of type int")
+	assert(t, app, "POST", "/users/"+bindID, strings.NewReader("a,b"), withContentType("text/csv"),
+		http.StatusBadRequest, "bind body: unsupported media type")
+}
+
+func TestBindErrorType(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		var in bindPage
+		err := c.Bind(&in)
+		var be *goweb.BindError
+		if !errors.As(err, &be) {
+			return c.Text(http.StatusInternalServerError, "not a BindError")
+		}
+		return c.Text(http.StatusBadRequest, be.Source+" "+be.Field)
+	})
+	assert(t, app, "GET", "/?limit=-", nil, nil, http.StatusBadRequest, "query Limit")
+}
+
+func TestBindTarget(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		var in bindPage
+		if err := c.Bind(in); err == nil {
+			return c.Text(http.StatusInternalServerError, "no error")
+		}
+		return c.Text(http.StatusOK, "")
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "")
+}
@@ -0,0 +1,298 @@
+package goweb
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// ConflictKind is the kind of a RouteConflict.
+type ConflictKind int
+
+const (
+	// ConflictDuplicate means that two routes have the same
+	// pattern, ignoring param names.
+	ConflictDuplicate ConflictKind = iota + 1
+
+	// ConflictShadowed means that every path matched by a
+	// route is matched by another route first, so the route
+	// is never reached.
+	ConflictShadowed
+
+	// ConflictOverlap means that some paths matched by a
+	// route are matched by another route first.
+	ConflictOverlap
+)
+
+func (k ConflictKind) String() string {
+	switch k {
+	case ConflictDuplicate:
+		return "duplicates"
+	case ConflictShadowed:
+		return "is shadowed by"
+	}
+	return "overlaps with"
+}
+
+// RouteConflict describes a route that is not reached for
+// some or all of the paths it matches, because another
+// route matches them first.
+type RouteConflict struct {
+	Kind    ConflictKind
+	Method  string
+	Pattern string
+	Other   string
+}
+
+func (c RouteConflict) String() string {
+	return fmt.Sprintf("%s %s %s %s %s", c.Method, c.Pattern, c.Kind, c.Method, c.Other)
+}
+
+// ConflictError is returned by Engine.Validate if any route
+// conflicts with another.
+type ConflictError struct {
+	Conflicts []RouteConflict
+}
+
+func (e *ConflictError) Error() string {
+	conflicts := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		conflicts[i] = c.String()
+	}
+	return "route conflicts: " + strings.Join(conflicts, "; ")
+}
+
+// fatal reports whether any route is never reached.
+func (e *ConflictError) fatal() bool {
+	for _, c := range e.Conflicts {
+		if c.Kind != ConflictOverlap {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the registered routes for conflicts. It
+// reports routes with the same pattern and matchers as an
+// earlier route, and routes without matchers for which
+// sample paths built from the pattern are matched by
+// another route. Routes with a MatcherFunc are not
+// checked. Mounts are only checked for duplicate prefixes,
+// since routes below a prefix take priority over its mount.
+// A *ConflictError listing every conflict is returned if
+// any are found.
+func (e *Engine) Validate() error {
+	var conflicts []RouteConflict
+	seen := make(map[string]*route)
+	seenMounts := make(map[*mount]bool)
+	t := e.load()
+	for _, rt := range t.routes {
+		if rt.mount != nil {
+			if seenMounts[rt.mount] {
+				continue
+			}
+			seenMounts[rt.mount] = true
+			key := "mount " + rt.parsed.canonical()
+			if rt.host != nil {
+				key = rt.host.pattern + " " + key
+			}
+			if other, ok := seen[key]; ok {
+				conflicts = append(conflicts, RouteConflict{
+					Kind:    ConflictDuplicate,
+					Method:  anyMethod,
+					Pattern: rt.displayPattern(),
+					Other:   other.displayPattern(),
+				})
+				continue
+			}
+			seen[key] = rt
+			continue
+		}
+		key := rt.method + " " + rt.parsed.canonical()
+		if rt.host != nil {
+			key = rt.host.pattern + " " + key
+		}
+		matchers, ok := rt.matcherKey()
+		if !ok {
+			continue
+		}
+		key += " " + matchers
+		if other, ok := seen[key]; ok {
+			conflicts = append(conflicts, RouteConflict{
+				Kind:    ConflictDuplicate,
+				Method:  rt.method,
+				Pattern: rt.pattern,
+				Other:   other.pattern,
+			})
+			continue
+		}
+		seen[key] = rt
+		if len(rt.matchers) > 0 {
+			continue
+		}
+		if c, ok := t.checkSamples(rt); ok {
+			conflicts = append(conflicts, c)
+		}
+	}
+	if len(conflicts) > 0 {
+		return &ConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// checkSamples looks up sample paths for the route, and
+// returns a conflict with the first other route that
+// matches any of them.
+func (t *table) checkSamples(rt *route) (RouteConflict, bool) {
+	var other *route
+	lost := 0
+	samples := rt.parsed.samples()
+	for _, sample := range samples {
+		found, _ := lookupTree(t.treesFor(rt.host)[rt.method], sample, nil, nil)
+		if found == nil || found == rt {
+			continue
+		}
+		if other == nil {
+			other = found
+		}
+		lost++
+	}
+	if other == nil {
+		return RouteConflict{}, false
+	}
+	kind := ConflictOverlap
+	if lost == len(samples) {
+		kind = ConflictShadowed
+	}
+	return RouteConflict{
+		Kind:    kind,
+		Method:  rt.method,
+		Pattern: rt.pattern,
+		Other:   other.displayPattern(),
+	}, true
+}
+
+// displayPattern returns the pattern of the route, or the
+// prefix of its mount.
+func (rt *route) displayPattern() string {
+	if rt.mount == nil {
+		return rt.pattern
+	}
+	if rt.mount.prefix == "" {
+		return "/"
+	}
+	return rt.mount.prefix
+}
+
+// canonical returns the pattern with param names removed,
+// so that patterns matching the same paths are equal.
+func (p *Pattern) canonical() string {
+	var b strings.Builder
+	for _, t := range p.tokens {
+		if t.isParam() {
+			b.WriteString("{:" + t.paramRegexp() + "}")
+		} else {
+			b.WriteString(t.literal)
+		}
+	}
+	return b.String()
+}
+
+// samples returns paths matched by the pattern. Params are
+// filled with the shortest and a longer value matching
+// their regexp.
+func (p *Pattern) samples() []string {
+	var samples []string
+	for _, long := range []bool{false, true} {
+		var b strings.Builder
+		for _, t := range p.tokens {
+			if !t.isParam() {
+				b.WriteString(t.literal)
+				continue
+			}
+			constraint := t.constraint
+			if constraint == "" {
+				constraint = `[^\/]+`
+			}
+			re, err := syntax.Parse(constraint, syntax.Perl)
+			if err != nil {
+				return nil
+			}
+			writeSample(&b, re.Simplify(), long)
+		}
+		samples = append(samples, b.String())
+	}
+	return samples
+}
+
+// writeSample writes a string matched by re. Repeats are
+// written the minimum number of times, or at least once if
+// long is set. Alternations use the first or, if long is
+// set, the last alternative, and character classes are
+// sampled with sampleRune.
+func writeSample(b *strings.Builder, re *syntax.Regexp, long bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(sampleRune(re.Rune, long))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a')
+	case syntax.OpCapture:
+		writeSample(b, re.Sub[0], long)
+	case syntax.OpStar, syntax.OpQuest:
+		if long {
+			writeSample(b, re.Sub[0], long)
+		}
+	case syntax.OpPlus:
+		writeSample(b, re.Sub[0], long)
+	case syntax.OpRepeat:
+		n := re.Min
+		if long && n == 0 && re.Max != 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			writeSample(b, re.Sub[0], long)
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeSample(b, sub, long)
+		}
+	case syntax.OpAlternate:
+		if long {
+			writeSample(b, re.Sub[len(re.Sub)-1], long)
+		} else {
+			writeSample(b, re.Sub[0], long)
+		}
+	}
+}
+
+// sampleRune returns a rune in the given character class
+// ranges, preferring letters and digits over other runes.
+// Letters and digits from the start of the alphabet are
+// preferred, or from the end if long is set.
+func sampleRune(ranges []rune, long bool) rune {
+	preferred := []rune{'a', 'x', '0', 'A'}
+	if long {
+		preferred = []rune{'z', 'x', '9', 'Z'}
+	}
+	for _, r := range preferred {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r
+			}
+		}
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r < ranges[i]+128; r++ {
+			if unicode.IsPrint(r) && r != '/' {
+				return r
+			}
+		}
+	}
+	if len(ranges) > 0 {
+		return ranges[0]
+	}
+	return 'a'
+}
@@ -0,0 +1,95 @@
+package goweb_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func assertConflicts(t *testing.T, app *goweb.Engine, want ...goweb.RouteConflict) {
+	err := app.Validate()
+	if len(want) == 0 {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+	var cerr *goweb.ConflictError
+	if !errors.As(err, &cerr) {
+		t.Errorf("expected *ConflictError; got %v", err)
+		return
+	}
+	if !reflect.DeepEqual(cerr.Conflicts, want) {
+		t.Errorf("wrong conflicts:\ngot  %v\nwant %v", cerr.Conflicts, want)
+	}
+}
+
+func TestValidateNoConflicts(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id}", nopHandler)
+	app.GET("/users/me", nopHandler)
+	app.GET("/users/{id}/posts", nopHandler)
+	app.POST("/users/{id}", nopHandler)
+	app.GET("/files/{path:.*}", nopHandler)
+	assertConflicts(t, app)
+}
+
+func TestValidateDuplicate(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id}", nopHandler)
+	app.GET("/users/{uid}", nopHandler)
+	assertConflicts(t, app, goweb.RouteConflict{
+		Kind:    goweb.ConflictDuplicate,
+		Method:  "GET",
+		Pattern: "/users/{uid}",
+		Other:   "/users/{id}",
+	})
+}
+
+func TestValidateShadowed(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id:[0-9]}", nopHandler)
+	app.GET("/users/{id:[0-9]+}", nopHandler)
+	assertConflicts(t, app, goweb.RouteConflict{
+		Kind:    goweb.ConflictShadowed,
+		Method:  "GET",
+		Pattern: "/users/{id:[0-9]}",
+		Other:   "/users/{id:[0-9]+}",
+	})
+}
+
+func TestValidateOverlap(t *testing.T) {
+	app := goweb.New()
+	app.GET("/{code:[a-c]+}", nopHandler)
+	app.GET("/{word:[a-z]+}", nopHandler)
+	assertConflicts(t, app, goweb.RouteConflict{
+		Kind:    goweb.ConflictOverlap,
+		Method:  "GET",
+		Pattern: "/{word:[a-z]+}",
+		Other:   "/{code:[a-c]+}",
+	})
+}
+
+func TestConflictErrorMessage(t *testing.T) {
+	app := goweb.New()
+	app.GET("/a/{x}", nopHandler)
+	app.GET("/a/{y}", nopHandler)
+	err := app.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	equals(t, err.Error(), "route conflicts: GET /a/{y} duplicates GET /a/{x}")
+}
+
+func TestRunFatalShadowing(t *testing.T) {
+	app := goweb.New()
+	app.FatalShadowing(true)
+	app.GET("/a/{x}", nopHandler)
+	app.GET("/a/{y}", nopHandler)
+	var cerr *goweb.ConflictError
+	if err := app.Run(":9998"); !errors.As(err, &cerr) {
+		t.Errorf("expected *ConflictError; got %v", err)
+	}
+}
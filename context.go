@@ -1,20 +1,52 @@
 package goweb
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"time"
 )
 
 // Context provides helper methods to read the request, get
 // and set values in a data store, and send a response to
-// the client. A new Context is constructed for each
-// request, and is dropped when the response is sent.
+// the client. Contexts are pooled: once the response is
+// sent, the Context is reset and reused for another
+// request. Handlers must not keep a Context, or a response
+// built from it, after they return, for example by using it
+// in a goroutine. Values needed later must be copied out.
 type Context struct {
 	ResponseWriter http.ResponseWriter
 	Request        *http.Request
 	params         params
-	store          Map
+	store          map[interface{}]interface{}
 	loggers        []Logger
+	engine         *Engine
+
+	// The first response of each kind built for a request is
+	// stored in the Context, so that it isn't allocated.
+	json     JSONResponse
+	text     TextResponse
+	empty    EmptyResponse
+	redirect RedirectResponse
+}
+
+// reset clears the Context for reuse, keeping the memory of
+// its params and store.
+func (c *Context) reset() {
+	c.ResponseWriter = nil
+	c.Request = nil
+	for i := range c.params {
+		c.params[i] = param{}
+	}
+	c.params = c.params[:0]
+	for key := range c.store {
+		delete(c.store, key)
+	}
+	c.loggers = nil
+	c.json = JSONResponse{}
+	c.text = TextResponse{}
+	c.empty = EmptyResponse{}
+	c.redirect = RedirectResponse{}
 }
 
 // Param gets a path parameter by the given name. An Empty
@@ -33,6 +65,9 @@ func (c *Context) Query(name string) string {
 
 // Set sets a value in the Context data store.
 func (c *Context) Set(key string, value interface{}) {
+	if c.store == nil {
+		c.store = make(map[interface{}]interface{})
+	}
 	c.store[key] = value
 }
 
@@ -41,6 +76,107 @@ func (c *Context) Get(key string) interface{} {
 	return c.store[key]
 }
 
+// Deadline returns the deadline of the request's context.
+// It implements the context.Context interface, so that the
+// Context can be passed to functions taking a
+// context.Context while the handler runs. Once the handler
+// returns, the Context is done. Use WithTimeout for a
+// context that may outlive the handler.
+func (c *Context) Deadline() (time.Time, bool) {
+	if c.Request == nil {
+		return time.Time{}, false
+	}
+	return c.Request.Context().Deadline()
+}
+
+// Done returns a channel that is closed when the request's
+// context is done, such as when the client disconnects.
+func (c *Context) Done() <-chan struct{} {
+	if c.Request == nil {
+		return closedDone
+	}
+	return c.Request.Context().Done()
+}
+
+// Err returns the error of the request's context once it is
+// done.
+func (c *Context) Err() error {
+	if c.Request == nil {
+		return context.Canceled
+	}
+	return c.Request.Context().Err()
+}
+
+// Value returns the value for the key in the request's
+// context or, if it has none and the key is a string or a
+// Key, in the Context data store.
+func (c *Context) Value(key interface{}) interface{} {
+	if c.Request == nil {
+		return nil
+	}
+	if v := c.Request.Context().Value(key); v != nil {
+		return v
+	}
+	return storeValue(c.store, key)
+}
+
+// WithTimeout returns a context that is done after the given
+// duration, or when the request is done. It is derived from
+// the request's context and a copy of the Context data
+// store, so unlike the Context it can be used after the
+// handler returns, such as in a goroutine. If it is called
+// after the handler returns, the context is already done.
+func (c *Context) WithTimeout(d time.Duration) (context.Context, context.CancelFunc) {
+	if c.Request == nil {
+		parent, cancel := context.WithCancel(context.Background())
+		cancel()
+		return context.WithTimeout(parent, d)
+	}
+	store := make(map[interface{}]interface{}, len(c.store))
+	for k, v := range c.store {
+		store[k] = v
+	}
+	return context.WithTimeout(&storeContext{Context: c.Request.Context(), store: store}, d)
+}
+
+// closedDone is the Done channel of a Context after its
+// handler returns.
+var closedDone = func() chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}()
+
+// storeContext is a context whose values fall back to those
+// of a copied Context data store.
+type storeContext struct {
+	context.Context
+	store map[interface{}]interface{}
+}
+
+func (s *storeContext) Value(key interface{}) interface{} {
+	if v := s.Context.Value(key); v != nil {
+		return v
+	}
+	return storeValue(s.store, key)
+}
+
+// storeValue returns the value for the key in the store if
+// the key is a string or a Key.
+func storeValue(store map[interface{}]interface{}, key interface{}) interface{} {
+	switch key.(type) {
+	case string, storeKey:
+		return store[key]
+	}
+	return nil
+}
+
+// URL builds the path of the route with the given name. See
+// Engine.URL.
+func (c *Context) URL(name string, pairs ...string) (string, error) {
+	return c.engine.URL(name, pairs...)
+}
+
 // ParseJSON parses the request body into the given target.
 func (c *Context) ParseJSON(target interface{}) error {
 	return json.NewDecoder(c.Request.Body).Decode(target)
@@ -117,28 +253,43 @@ func (c *Context) LogEmergency(messages ...interface{}) {
 
 // JSON returns a JSONResponse.
 func (c *Context) JSON(statusCode int, value interface{}) *JSONResponse {
-	return &JSONResponse{
+	res := &c.json
+	if res.context != nil {
+		res = new(JSONResponse)
+	}
+	*res = JSONResponse{
 		context: c,
 		body:    value,
 		status:  statusCode,
 	}
+	return res
 }
 
 // Text returns a TextResponse.
 func (c *Context) Text(statusCode int, text string) *TextResponse {
-	return &TextResponse{
+	res := &c.text
+	if res.context != nil {
+		res = new(TextResponse)
+	}
+	*res = TextResponse{
 		context: c,
 		body:    text,
 		status:  statusCode,
 	}
+	return res
 }
 
 // Empty returns a EmptyResponse.
 func (c *Context) Empty(statusCode int) *EmptyResponse {
-	return &EmptyResponse{
+	res := &c.empty
+	if res.context != nil {
+		res = new(EmptyResponse)
+	}
+	*res = EmptyResponse{
 		context: c,
 		status:  statusCode,
 	}
+	return res
 }
 
 // Nil does not return any response.
@@ -148,9 +299,28 @@ func (c *Context) Nil() *NilResponse {
 
 // Redirect redirects the request.
 func (c *Context) Redirect(statusCode int, url string) *RedirectResponse {
-	return &RedirectResponse{
+	return c.redirectResponse(statusCode, url, nil)
+}
+
+func (c *Context) redirectResponse(statusCode int, url string, err error) *RedirectResponse {
+	res := &c.redirect
+	if res.context != nil {
+		res = new(RedirectResponse)
+	}
+	*res = RedirectResponse{
 		context: c,
 		status:  statusCode,
 		url:     url,
+		err:     err,
 	}
+	return res
+}
+
+// RedirectRoute redirects the request to the route with the
+// given name. Params are given as name value pairs. If the
+// URL can't be built, the error is logged and a 500
+// response is sent instead.
+func (c *Context) RedirectRoute(statusCode int, name string, pairs ...string) *RedirectResponse {
+	url, err := c.URL(name, pairs...)
+	return c.redirectResponse(statusCode, url, err)
 }
@@ -1,10 +1,13 @@
 package goweb_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/twharmon/goweb"
 )
@@ -213,3 +216,166 @@ func TestRedirect(t *testing.T) {
 		t.Errorf("handler returned unexpected location header: got '%v' want '%v'", rr.Result().Header.Get("Location"), "/foo")
 	}
 }
+
+func TestContextURL(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id}", nopHandler).Name("user")
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		url, err := c.URL("user", "id", "5")
+		if err != nil {
+			return c.Text(http.StatusInternalServerError, err.Error())
+		}
+		return c.Text(http.StatusOK, url)
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "/users/5")
+}
+
+func TestRedirectRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id}", nopHandler).Name("user")
+	app.GET("/me", func(c *goweb.Context) goweb.Responder {
+		return c.RedirectRoute(http.StatusFound, "user", "id", "5")
+	})
+	app.GET("/broken", func(c *goweb.Context) goweb.Responder {
+		return c.RedirectRoute(http.StatusFound, "missing")
+	})
+	assertHeader(t, app, "GET", "/me", "Location", "/users/5")
+	req, err := http.NewRequest("GET", "/broken", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	app.ServeHTTP(rr, req)
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusInternalServerError)
+	}
+}
+
+var _ context.Context = (*goweb.Context)(nil)
+
+func TestContextValue(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		c.Set("user", "gopher")
+		c.Set("trace", "store")
+		return c.Text(http.StatusOK, c.Value("user").(string)+" "+c.Value(ctxKey("trace")).(string))
+	})
+	assert(t, app, "GET", "/", nil, func(r *http.Request) {
+		*r = *r.WithContext(context.WithValue(r.Context(), ctxKey("trace"), "request"))
+	}, http.StatusOK, "gopher request")
+}
+
+func TestContextDone(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		select {
+		case <-c.Done():
+		default:
+			t.Errorf("expected context to be done")
+		}
+		if !errors.Is(c.Err(), context.Canceled) {
+			t.Errorf("expected context.Canceled; got %v", c.Err())
+		}
+		return c.Empty(http.StatusOK)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert(t, app, "GET", "/", nil, func(r *http.Request) {
+		*r = *r.WithContext(ctx)
+	}, http.StatusOK, "")
+}
+
+func TestContextDeadline(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		if d, ok := c.Deadline(); !ok || !d.Equal(deadline) {
+			t.Errorf("expected deadline %v; got %v %v", deadline, d, ok)
+		}
+		return c.Empty(http.StatusOK)
+	})
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	assert(t, app, "GET", "/", nil, func(r *http.Request) {
+		*r = *r.WithContext(ctx)
+	}, http.StatusOK, "")
+}
+
+func TestContextWithTimeout(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		c.Set("user", "gopher")
+		ctx, cancel := c.WithTimeout(time.Millisecond)
+		defer cancel()
+		if ctx.Value("user") != "gopher" {
+			t.Errorf("expected derived context to see the store")
+		}
+		<-ctx.Done()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded; got %v", ctx.Err())
+		}
+		if c.Err() != nil {
+			t.Errorf("expected request context not to be done")
+		}
+		return c.Empty(http.StatusOK)
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "")
+}
+
+func TestContextWithTimeoutOutlivesHandler(t *testing.T) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		c.Set("user", c.Query("user"))
+		if ctx == nil {
+			ctx, cancel = c.WithTimeout(10 * time.Millisecond)
+		}
+		return c.Empty(http.StatusOK)
+	})
+	assert(t, app, "GET", "/?user=gopher", nil, nil, http.StatusOK, "")
+	defer cancel()
+	assert(t, app, "GET", "/?user=other", nil, nil, http.StatusOK, "")
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded; got %v", ctx.Err())
+	}
+	if ctx.Value("user") != "gopher" {
+		t.Errorf("expected user gopher; got %v", ctx.Value("user"))
+	}
+}
+
+func TestContextAfterHandler(t *testing.T) {
+	var retained *goweb.Context
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		c.Set("user", "gopher")
+		retained = c
+		return c.Empty(http.StatusOK)
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "")
+	select {
+	case <-retained.Done():
+	default:
+		t.Errorf("expected context to be done")
+	}
+	if !errors.Is(retained.Err(), context.Canceled) {
+		t.Errorf("expected context.Canceled; got %v", retained.Err())
+	}
+	if _, ok := retained.Deadline(); ok {
+		t.Errorf("expected no deadline")
+	}
+	if retained.Value("user") != nil {
+		t.Errorf("expected no value; got %v", retained.Value("user"))
+	}
+	ctx, cancel := retained.WithTimeout(time.Hour)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+	default:
+		t.Errorf("expected derived context to be done")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("expected context.Canceled; got %v", ctx.Err())
+	}
+}
@@ -2,104 +2,197 @@ package goweb
 
 import (
 	"context"
-	"fmt"
+	"log"
 	"net/http"
-	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Engine contains routing and logging information for your
 // app.
 type Engine struct {
-	server        *http.Server
-	getRoutes     []*route
-	putRoutes     []*route
-	postRoutes    []*route
-	patchRoutes   []*route
-	deleteRoutes  []*route
-	headRoutes    []*route
-	optionsRoutes []*route
+	server *http.Server
+	pool   sync.Pool
 
-	notFoundHandler Handler
+	// mu guards the registry of routes and hosts. Requests
+	// are routed with a snapshot of the registry.
+	mu     sync.Mutex
+	routes []*route
+	hosts  []*hostPattern
+	table  atomic.Value
+
+	notFoundHandler         Handler
+	methodNotAllowedHandler Handler
+	notImplementedHandler   Handler
+
+	autoHead    bool
+	autoOptions bool
+
+	fatalShadowing  bool
+	cleanPath       PathPolicy
+	trailingSlash   PathPolicy
+	caseInsensitive PathPolicy
+	syntax          Syntax
+	rawPath         bool
+	versioning      Versioning
+
+	validationRules map[string]ValidationRule
 
 	loggers []Logger
+
+	httpMiddleware []func(http.Handler) http.Handler
+	handler        http.Handler
 }
 
-var paramNameRegExp = regexp.MustCompile(`{([a-zA-Z0-9-]+):?(.*?)}`)
+func (e *Engine) registerRoute(host *hostPattern, method string, path string, chain []Handler, handler Handler) *Route {
+	return e.addRoute(e.newRoute(host, method, path, chain, handler))
+}
 
-func (e *Engine) registerRoute(method string, path string, handler Handler) {
-	rt := getRouteFromPath(path)
-	rt.handler = handler
+// newRoute compiles a route without registering it. It
+// panics if the method is empty or the pattern is
+// malformed.
+func (e *Engine) newRoute(host *hostPattern, method string, path string, chain []Handler, handler Handler) *route {
+	if method == "" {
+		panic("method for path '" + path + "' is empty")
+	}
+	rt := getRouteFromPath(path, e.syntax)
+	rt.handler = applyMiddleware(chain, handler)
+	rt.middleware = len(chain)
 	rt.method = method
-	switch method {
-	case http.MethodGet:
-		e.getRoutes = append(e.getRoutes, rt)
-	case http.MethodPut:
-		e.putRoutes = append(e.putRoutes, rt)
-	case http.MethodPatch:
-		e.patchRoutes = append(e.patchRoutes, rt)
-	case http.MethodPost:
-		e.postRoutes = append(e.postRoutes, rt)
-	case http.MethodDelete:
-		e.deleteRoutes = append(e.deleteRoutes, rt)
-	case http.MethodHead:
-		e.headRoutes = append(e.headRoutes, rt)
-	case http.MethodOptions:
-		e.optionsRoutes = append(e.optionsRoutes, rt)
-	}
-}
-
-func getRouteFromPath(path string) *route {
-	rt := new(route)
-	if path[0] != '/' {
-		panic("path '" + path + "' does not start with '/'")
-	}
-	pathRegExpStr := "^" + path + "$"
-	matches := paramNameRegExp.FindAllStringSubmatch(path, -1)
-	for _, match := range matches {
-		if match[2] == "" {
-			match[2] = `[^\/]*`
+	if host != nil {
+		for _, name := range host.paramNames {
+			if contains(rt.paramNames, name) {
+				panic("param '" + name + "' of path '" + path + "' is also a param of host '" + host.pattern + "'")
+			}
+		}
+		rt.host = host
+		rt.paramNames = append(host.paramNames[:len(host.paramNames):len(host.paramNames)], rt.paramNames...)
+	}
+	return rt
+}
+
+// addRoute registers a route. It is safe to call while
+// requests are being served; they are routed with the
+// routes registered when they arrived.
+func (e *Engine) addRoute(rt *route) *Route {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.routes = append(e.routes, rt)
+	e.invalidate()
+	return &Route{
+		route:  rt,
+		engine: e,
+	}
+}
+
+// Remove removes the routes for the given method whose
+// pattern matches the same paths as the given pattern,
+// ignoring param names, and which were registered without a
+// host. It reports whether any route was removed. It is
+// safe to call while requests are being served.
+func (e *Engine) Remove(method string, path string) bool {
+	return e.removeRoutes(nil, method, path)
+}
+
+func (e *Engine) removeRoutes(host *hostPattern, method string, path string) bool {
+	key := getRouteFromPath(path, e.syntax).parsed.canonical()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	routes := make([]*route, 0, len(e.routes))
+	for _, rt := range e.routes {
+		if rt.host != host || rt.method != method || rt.parsed.canonical() != key {
+			routes = append(routes, rt)
+		}
+	}
+	if len(routes) == len(e.routes) {
+		return false
+	}
+	e.routes = routes
+	e.invalidate()
+	return true
+}
+
+// getRouteFromPath compiles a path pattern written in the
+// given syntax into a route. It panics if the pattern is
+// malformed. Static text and unconstrained params that span
+// a whole segment are matched by the routing tree, and a
+// trailing {name...}, *name or {name:.*} param becomes a
+// catch-all. Once a param has a constraint
+// or shares its segment with other text, the rest of the
+// pattern is matched by a regexp. See node.lookup for the
+// order in which routes are matched.
+func getRouteFromPath(path string, syntax Syntax) *route {
+	pattern, err := ParsePatternSyntax(path, syntax)
+	if err != nil {
+		panic(err)
+	}
+	rt := &route{
+		pattern:    path,
+		parsed:     pattern,
+		paramNames: pattern.Params(),
+	}
+	tokens := pattern.tokens
+	for i, t := range tokens {
+		if !t.isParam() {
+			rt.parts = append(rt.parts, routePart{kind: routePartStatic, static: t.literal})
+			continue
+		}
+		last := i == len(tokens)-1
+		switch {
+		case t.constraint == "" && (last || strings.HasPrefix(tokens[i+1].literal, "/")):
+			rt.parts = append(rt.parts, routePart{kind: routePartParam})
+		case t.constraint == catchAllConstraint && last:
+			rt.parts = append(rt.parts, routePart{kind: routePartCatchAll})
+		default:
+			rt.parts = append(rt.parts, pattern.regexpPart(i))
+			return rt
 		}
-		rt.paramNames = append(rt.paramNames, match[1])
-		paramInfoRegExp := regexp.MustCompile(fmt.Sprintf("{%s:?(.*?)}", match[1]))
-		pathRegExpStr = paramInfoRegExp.ReplaceAllString(pathRegExpStr, "("+match[2]+")")
 	}
-	rt.regexp = regexp.MustCompile(pathRegExpStr)
 	return rt
 }
 
+// Handle registers a route for the given method. Any
+// method can be used, including extension methods such as
+// PROPFIND or PURGE.
+func (e *Engine) Handle(method string, path string, handler Handler) *Route {
+	return e.registerRoute(nil, method, path, nil, handler)
+}
+
 // GET registers a route for method GET.
-func (e *Engine) GET(path string, handler Handler) {
-	e.registerRoute(http.MethodGet, path, handler)
+func (e *Engine) GET(path string, handler Handler) *Route {
+	return e.registerRoute(nil, http.MethodGet, path, nil, handler)
 }
 
 // PUT registers a route for method PUT.
-func (e *Engine) PUT(path string, handler Handler) {
-	e.registerRoute(http.MethodPut, path, handler)
+func (e *Engine) PUT(path string, handler Handler) *Route {
+	return e.registerRoute(nil, http.MethodPut, path, nil, handler)
 }
 
 // POST registers a route for method POST.
-func (e *Engine) POST(path string, handler Handler) {
-	e.registerRoute(http.MethodPost, path, handler)
+func (e *Engine) POST(path string, handler Handler) *Route {
+	return e.registerRoute(nil, http.MethodPost, path, nil, handler)
 }
 
 // PATCH registers a route for method PATCH.
-func (e *Engine) PATCH(path string, handler Handler) {
-	e.registerRoute(http.MethodPatch, path, handler)
+func (e *Engine) PATCH(path string, handler Handler) *Route {
+	return e.registerRoute(nil, http.MethodPatch, path, nil, handler)
 }
 
 // DELETE registers a route for method DELETE.
-func (e *Engine) DELETE(path string, handler Handler) {
-	e.registerRoute(http.MethodDelete, path, handler)
+func (e *Engine) DELETE(path string, handler Handler) *Route {
+	return e.registerRoute(nil, http.MethodDelete, path, nil, handler)
 }
 
 // HEAD registers a route for method HEAD.
-func (e *Engine) HEAD(path string, handler Handler) {
-	e.registerRoute(http.MethodHead, path, handler)
+func (e *Engine) HEAD(path string, handler Handler) *Route {
+	return e.registerRoute(nil, http.MethodHead, path, nil, handler)
 }
 
 // OPTIONS registers a route for method OPTIONS.
-func (e *Engine) OPTIONS(path string, handler Handler) {
-	e.registerRoute(http.MethodOptions, path, handler)
+func (e *Engine) OPTIONS(path string, handler Handler) *Route {
+	return e.registerRoute(nil, http.MethodOptions, path, nil, handler)
 }
 
 // Middleware returns a new middleware chain.
@@ -110,61 +203,228 @@ func (e *Engine) Middleware(middleware ...Handler) *Middleware {
 	}
 }
 
+// Group returns a new group of routes with the given path
+// prefix and middleware.
+func (e *Engine) Group(prefix string, middleware ...Handler) *Group {
+	return newGroup(e, nil, "", prefix, joinChains(nil, middleware))
+}
+
 // NotFound registers a handler to be called if no route is
 // matched.
 func (e *Engine) NotFound(handler Handler) {
 	e.notFoundHandler = handler
 }
 
+// MethodNotAllowed registers a handler to be called if no
+// route is matched for the request method, but a route is
+// matched for another method. The Allow header is set
+// before the handler is called.
+func (e *Engine) MethodNotAllowed(handler Handler) {
+	e.methodNotAllowedHandler = handler
+}
+
+// NotImplemented registers a handler to be called if the
+// request method is not a standard method and no route is
+// registered for it.
+func (e *Engine) NotImplemented(handler Handler) {
+	e.notImplementedHandler = handler
+}
+
+// AutoHead sets whether HEAD requests without a matching
+// HEAD route are handled by the matching GET route. The
+// body written by the GET handler is discarded, and its
+// headers and Content-Length are kept. It is enabled by
+// default.
+func (e *Engine) AutoHead(enabled bool) {
+	e.autoHead = enabled
+}
+
+// AutoOptions sets whether OPTIONS requests without a
+// matching OPTIONS route are answered with a 204 response
+// listing the allowed methods for the path in the Allow
+// header. It is enabled by default.
+func (e *Engine) AutoOptions(enabled bool) {
+	e.autoOptions = enabled
+}
+
+// PatternSyntax sets the syntax of the patterns of routes
+// registered after it is called. See Syntax.
+func (e *Engine) PatternSyntax(syntax Syntax) {
+	e.syntax = syntax
+}
+
+// FatalShadowing sets whether Run refuses to start if
+// Validate finds duplicate or shadowed routes. Otherwise
+// route conflicts are logged, and the server is started.
+func (e *Engine) FatalShadowing(enabled bool) {
+	e.fatalShadowing = enabled
+}
+
+// UseHTTP registers middleware written for net/http, such
+// as CORS or tracing handlers, to run around the routing of
+// every request. Middleware runs in the order in which it
+// was registered, before any route is matched, and the
+// request and ResponseWriter it passes on are the ones seen
+// by the Context.
+func (e *Engine) UseHTTP(middleware ...func(http.Handler) http.Handler) {
+	e.httpMiddleware = append(e.httpMiddleware, middleware...)
+	var h http.Handler = http.HandlerFunc(e.serve)
+	for i := len(e.httpMiddleware) - 1; i >= 0; i-- {
+		h = e.httpMiddleware[i](h)
+	}
+	e.handler = h
+}
+
 // ServeHTTP implements the http.Handler interface.
 func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		e.serve(w, r, e.getRoutes)
-	case http.MethodPost:
-		e.serve(w, r, e.postRoutes)
-	case http.MethodPut:
-		e.serve(w, r, e.putRoutes)
-	case http.MethodPatch:
-		e.serve(w, r, e.patchRoutes)
-	case http.MethodDelete:
-		e.serve(w, r, e.deleteRoutes)
-	case http.MethodHead:
-		e.serve(w, r, e.headRoutes)
-	case http.MethodOptions:
-		e.serve(w, r, e.optionsRoutes)
-	}
-}
-
-func (e *Engine) serve(w http.ResponseWriter, r *http.Request, routes []*route) {
-	c := &Context{
-		ResponseWriter: w,
-		Request:        r,
-		loggers:        e.loggers,
-	}
-	for _, route := range routes {
-		if route.regexp.MatchString(r.URL.Path) {
-			c.store = make(Map)
-			matches := route.regexp.FindAllStringSubmatch(r.URL.Path, -1)
-			for i := 1; i < len(matches[0]); i++ {
-				c.params = append(c.params, param{
-					key:   route.paramNames[i-1],
-					value: matches[0][i],
-				})
+	if e.handler != nil {
+		e.handler.ServeHTTP(w, r)
+		return
+	}
+	e.serve(w, r)
+}
+
+// serve routes the request to its handler.
+func (e *Engine) serve(w http.ResponseWriter, r *http.Request) {
+	c := e.getContext(w, r)
+	defer e.putContext(c)
+	t := e.load()
+	path := e.routingPath(r)
+	route, ps := t.lookup(r, r.Method, path, c.params)
+	if route == nil && r.Method == http.MethodHead && e.autoHead {
+		if route, ps = t.lookup(r, http.MethodGet, path, c.params); route != nil {
+			hw := &headResponseWriter{ResponseWriter: w}
+			defer hw.finish()
+			c.ResponseWriter = hw
+		}
+	}
+	if route == nil && !isStandardMethod(r.Method) {
+		route, ps = t.lookup(r, anyMethod, path, c.params)
+	}
+	if route == nil {
+		switch fixed, policy := e.fixPath(t, r, path); policy {
+		case PathPolicyRewrite:
+			e.serve(w, e.withPath(r, fixed))
+			return
+		case PathPolicyRedirect:
+			// Paths that would redirect to another host are
+			// not found.
+			if location, ok := e.redirectLocation(r, fixed); ok {
+				c.Redirect(redirectStatus(r.Method), location).Respond()
+				return
 			}
-			if res := route.handler(c); res != nil {
-				res.Respond()
+		}
+	}
+	handler := e.notFoundHandler
+	if route != nil {
+		if e.rawPath {
+			for i := range ps {
+				if route.mount != nil && ps[i].key == mountParam {
+					// Decoded by withPath in the mount
+					// handler.
+					continue
+				}
+				ps[i].value = unescapeParam(ps[i].value)
 			}
-			return
+		}
+		c.params = ps
+		handler = route.handler
+		if route.version != "" {
+			e.versioning.setPolicyHeaders(w.Header(), route.version)
+		}
+	} else if !t.hasMethod(r.Method) && !isStandardMethod(r.Method) {
+		handler = e.notImplementedHandler
+	} else if allow := e.allow(t, r, path); allow != "" {
+		w.Header().Set("Allow", allow)
+		handler = e.methodNotAllowedHandler
+		if r.Method == http.MethodOptions && e.autoOptions {
+			handler = optionsHandler
 		}
 	}
-	if res := e.notFoundHandler(c); res != nil {
+	if res := handler(c); res != nil {
 		res.Respond()
 	}
 }
 
-// Run starts a server on the given port.
+// getContext returns a Context for the request, reusing one
+// from the pool if possible.
+func (e *Engine) getContext(w http.ResponseWriter, r *http.Request) *Context {
+	c, _ := e.pool.Get().(*Context)
+	if c == nil {
+		c = &Context{engine: e}
+	}
+	c.ResponseWriter = w
+	c.Request = r
+	c.loggers = e.loggers
+	return c
+}
+
+// putContext returns the Context to the pool once the
+// response is sent.
+func (e *Engine) putContext(c *Context) {
+	c.reset()
+	e.pool.Put(c)
+}
+
+func isStandardMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func optionsHandler(c *Context) Responder {
+	return c.Empty(http.StatusNoContent)
+}
+
+// allow returns the value of the Allow header for the
+// request with the given path, listing every method with a
+// route matching it. The path "*" matches every method with
+// a route.
+func (e *Engine) allow(t *table, r *http.Request, path string) string {
+	var methods []string
+	for _, method := range t.methods() {
+		if path != "*" {
+			if route, _ := t.lookup(r, method, path, nil); route == nil {
+				continue
+			}
+		}
+		methods = append(methods, method)
+	}
+	if len(methods) == 0 {
+		return ""
+	}
+	if e.autoHead && contains(methods, http.MethodGet) && !contains(methods, http.MethodHead) {
+		methods = append(methods, http.MethodHead)
+	}
+	if e.autoOptions && !contains(methods, http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Run validates the routes and starts a server on the given
+// port. Route conflicts are logged, or returned if they are
+// fatal. See FatalShadowing.
 func (e *Engine) Run(port string) error {
+	if err := e.Validate(); err != nil {
+		if e.fatalShadowing && err.(*ConflictError).fatal() {
+			return err
+		}
+		log.Printf("goweb: %v", err)
+	}
 	e.server = &http.Server{
 		Addr:    port,
 		Handler: e,
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/twharmon/goweb"
 )
@@ -14,7 +15,14 @@ func TestRun(t *testing.T) {
 		return c.Empty(http.StatusOK)
 	})
 	go app.Run(":9999")
-	res, err := http.DefaultClient.Get("http://localhost:9999/")
+	var res *http.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		if res, err = http.DefaultClient.Get("http://localhost:9999/"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
 	if err != nil {
 		t.Error(err)
 		return
@@ -170,3 +178,361 @@ func TestParamsNotFound(t *testing.T) {
 	app.GET("/hello/{name}/{age}", handler)
 	assert(t, app, "GET", "/hello/Gopher/5", nil, nil, http.StatusOK, " 5")
 }
+
+func TestStaticBeforeParamRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "id "+c.Param("id"))
+	})
+	app.GET("/users/me", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "me")
+	})
+	assert(t, app, "GET", "/users/me", nil, nil, http.StatusOK, "me")
+	assert(t, app, "GET", "/users/mel", nil, nil, http.StatusOK, "id mel")
+	assert(t, app, "GET", "/users/m", nil, nil, http.StatusOK, "id m")
+}
+
+func TestSharedPrefixRoutes(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Request.URL.Path)
+	}
+	app := goweb.New()
+	for _, path := range []string{"/search", "/support", "/s", "/blog/{post}", "/blog/{post}/comments", "/"} {
+		app.GET(path, handler)
+	}
+	for _, path := range []string{"/search", "/support", "/s", "/blog/a", "/blog/a/comments", "/"} {
+		assert(t, app, "GET", path, nil, nil, http.StatusOK, path)
+	}
+	assert(t, app, "GET", "/sup", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+	assert(t, app, "GET", "/blog/a/b", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestConstrainedParamRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/hello/{name}/{age:[0-9]+}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("name")+" "+c.Param("age"))
+	})
+	assert(t, app, "GET", "/hello/Gopher/5", nil, nil, http.StatusOK, "Gopher 5")
+	assert(t, app, "GET", "/hello/Gopher/five", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestInSegmentParamRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/files/{name}.{ext}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("name")+" "+c.Param("ext"))
+	})
+	assert(t, app, "GET", "/files/a.b.txt", nil, nil, http.StatusOK, "a.b txt")
+	assert(t, app, "GET", "/files/readme", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestCatchAllRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/static/{path:.*}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("path"))
+	})
+	app.GET("/static/index", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "index")
+	})
+	assert(t, app, "GET", "/static/css/style.css", nil, nil, http.StatusOK, "css/style.css")
+	assert(t, app, "GET", "/static/index", nil, nil, http.StatusOK, "index")
+	assert(t, app, "GET", "/static/", nil, nil, http.StatusOK, "")
+}
+
+func TestStaticPathIsLiteral(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Empty(http.StatusOK)
+	}
+	app := goweb.New()
+	app.GET("/a.b/{c:[0-9]+}.json", handler)
+	assert(t, app, "GET", "/a.b/1.json", nil, nil, http.StatusOK, "")
+	assert(t, app, "GET", "/axb/1.json", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+	assert(t, app, "GET", "/a.b/1xjson", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Empty(http.StatusOK)
+	}
+	app := goweb.New()
+	app.GET("/users/{id}", handler)
+	app.DELETE("/users/{id}", handler)
+	app.POST("/users", handler)
+	assert(t, app, "PUT", "/users/1", nil, nil, http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+	assertHeader(t, app, "PUT", "/users/1", "Allow", "DELETE, GET, HEAD, OPTIONS")
+	assertHeader(t, app, "GET", "/users", "Allow", "OPTIONS, POST")
+	assert(t, app, "PUT", "/posts/1", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+	assertHeader(t, app, "PUT", "/posts/1", "Allow", "")
+}
+
+func TestCustomMethodNotAllowed(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Empty(http.StatusOK)
+	}
+	app := goweb.New()
+	app.GET("/", handler)
+	app.MethodNotAllowed(func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusMethodNotAllowed, c.ResponseWriter.Header().Get("Allow"))
+	})
+	assert(t, app, "POST", "/", nil, nil, http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS")
+}
+
+func TestAutoHead(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		c.ResponseWriter.Header().Set("X-Foo", "bar")
+		return c.Text(http.StatusOK, "hello")
+	})
+	assert(t, app, "HEAD", "/", nil, nil, http.StatusOK, "")
+	assertHeader(t, app, "HEAD", "/", "Content-Length", "5")
+	assertHeader(t, app, "HEAD", "/", "X-Foo", "bar")
+	assertHeader(t, app, "HEAD", "/", "Content-Type", "text/plain; charset=utf-8")
+}
+
+func TestAutoHeadDisabled(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "hello")
+	}
+	app := goweb.New()
+	app.AutoHead(false)
+	app.GET("/", handler)
+	assert(t, app, "HEAD", "/", nil, nil, http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+	assertHeader(t, app, "HEAD", "/", "Allow", "GET, OPTIONS")
+}
+
+func TestExplicitHeadRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "get")
+	})
+	app.HEAD("/", func(c *goweb.Context) goweb.Responder {
+		return c.Empty(http.StatusAccepted)
+	})
+	assert(t, app, "HEAD", "/", nil, nil, http.StatusAccepted, "")
+	assertHeader(t, app, "OPTIONS", "/", "Allow", "GET, HEAD, OPTIONS")
+}
+
+func TestAutoOptions(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Empty(http.StatusOK)
+	}
+	app := goweb.New()
+	app.GET("/users/{id}", handler)
+	app.PUT("/users/{id}", handler)
+	app.POST("/users", handler)
+	assert(t, app, "OPTIONS", "/users/1", nil, nil, http.StatusNoContent, "")
+	assertHeader(t, app, "OPTIONS", "/users/1", "Allow", "GET, HEAD, OPTIONS, PUT")
+	assertHeader(t, app, "OPTIONS", "*", "Allow", "GET, HEAD, OPTIONS, POST, PUT")
+	assert(t, app, "OPTIONS", "/posts", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestAutoOptionsDisabled(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Empty(http.StatusOK)
+	}
+	app := goweb.New()
+	app.AutoOptions(false)
+	app.GET("/", handler)
+	assert(t, app, "OPTIONS", "/", nil, nil, http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+	assertHeader(t, app, "OPTIONS", "/", "Allow", "GET, HEAD")
+}
+
+func TestHandle(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusMultiStatus, c.Request.Method)
+	}
+	app := goweb.New()
+	app.Handle("PROPFIND", "/files/{path:.*}", handler)
+	app.Handle(http.MethodGet, "/files/{path:.*}", handler)
+	assert(t, app, "PROPFIND", "/files/a/b", nil, nil, http.StatusMultiStatus, "PROPFIND")
+	assert(t, app, "GET", "/files/a/b", nil, nil, http.StatusMultiStatus, "GET")
+	assertHeader(t, app, "PUT", "/files/a/b", "Allow", "GET, HEAD, OPTIONS, PROPFIND")
+	assert(t, app, "PROPFIND", "/foo", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestNotImplemented(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Empty(http.StatusOK)
+	}
+	app := goweb.New()
+	app.GET("/", handler)
+	assert(t, app, "PURGE", "/", nil, nil, http.StatusNotImplemented, "{\"message\":\"Not Implemented\"}")
+	assert(t, app, "TRACE", "/", nil, nil, http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+}
+
+func TestCustomNotImplemented(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Empty(http.StatusOK)
+	}
+	app := goweb.New()
+	app.GET("/", handler)
+	app.NotImplemented(func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusNotImplemented, c.Request.Method)
+	})
+	assert(t, app, "PURGE", "/", nil, nil, http.StatusNotImplemented, "PURGE")
+}
+
+// permutations calls f with every permutation of the given
+// indexes.
+func permutations(indexes []int, f func([]int)) {
+	if len(indexes) <= 1 {
+		f(indexes)
+		return
+	}
+	for i := range indexes {
+		indexes[0], indexes[i] = indexes[i], indexes[0]
+		permutations(indexes[1:], func([]int) {
+			f(indexes)
+		})
+		indexes[0], indexes[i] = indexes[i], indexes[0]
+	}
+}
+
+func assertPriority(t *testing.T, patterns []string, requests map[string]string) {
+	indexes := make([]int, len(patterns))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	permutations(indexes, func(order []int) {
+		app := goweb.New()
+		for _, i := range order {
+			pattern := patterns[i]
+			app.GET(pattern, func(c *goweb.Context) goweb.Responder {
+				return c.Text(http.StatusOK, pattern)
+			})
+		}
+		for path, pattern := range requests {
+			assert(t, app, "GET", path, nil, nil, http.StatusOK, pattern)
+		}
+	})
+}
+
+func TestRoutePriority(t *testing.T) {
+	assertPriority(t, []string{
+		"/users/me",
+		"/users/{id:[0-9]+}",
+		"/users/{name}",
+		"/users/{path:.*}",
+	}, map[string]string{
+		"/users/me":    "/users/me",
+		"/users/12":    "/users/{id:[0-9]+}",
+		"/users/gus":   "/users/{name}",
+		"/users/a/b":   "/users/{path:.*}",
+		"/users/12/34": "/users/{path:.*}",
+	})
+}
+
+func TestRoutePriorityInSegment(t *testing.T) {
+	assertPriority(t, []string{
+		"/files/{name}",
+		"/files/{name}.{ext}",
+		"/files/{name}.{ext:json|xml}",
+		"/files/{path:.+}/edit",
+	}, map[string]string{
+		"/files/a":          "/files/{name}",
+		"/files/a.txt":      "/files/{name}.{ext}",
+		"/files/a.json":     "/files/{name}.{ext:json|xml}",
+		"/files/a/edit":     "/files/{path:.+}/edit",
+		"/files/a/b/c/edit": "/files/{path:.+}/edit",
+	})
+}
+
+func TestRoutePriorityConstraints(t *testing.T) {
+	assertPriority(t, []string{
+		"/v{major:[0-9]+}/docs",
+		"/{page}/docs",
+		"/{a:[a-z]+}/{b}",
+	}, map[string]string{
+		"/v2/docs":  "/v{major:[0-9]+}/docs",
+		"/vx/docs":  "/{a:[a-z]+}/{b}",
+		"/Vx/docs":  "/{page}/docs",
+		"/abc/docs": "/{a:[a-z]+}/{b}",
+	})
+}
+
+func TestRemove(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id}", pathHandler)
+	app.POST("/users/{id}", pathHandler)
+	assert(t, app, "GET", "/users/4", nil, nil, http.StatusOK, "/users/4")
+	if !app.Remove("GET", "/users/{name}") {
+		t.Fatalf("expected route to be removed")
+	}
+	if app.Remove("GET", "/users/{name}") {
+		t.Fatalf("expected no route to be removed")
+	}
+	assert(t, app, "GET", "/users/4", nil, nil, http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+	assert(t, app, "POST", "/users/4", nil, nil, http.StatusOK, "/users/4")
+}
+
+func TestRemoveNamedRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id}", pathHandler).Name("user")
+	app.Remove("GET", "/users/{id}")
+	if _, err := app.URL("user", "id", "4"); err == nil {
+		t.Fatalf("expected error for removed route")
+	}
+	app.GET("/people/{id}", pathHandler).Name("user")
+	assertURL(t, app, "user", []string{"id", "4"}, "/people/4")
+}
+
+func TestGroupRemove(t *testing.T) {
+	app := goweb.New()
+	api := app.Host("api.example.com").Group("/api")
+	api.GET("/users", pathHandler)
+	app.GET("/api/users", pathHandler)
+	if !api.Remove("GET", "/users") {
+		t.Fatalf("expected route to be removed")
+	}
+	assert(t, app, "GET", "/api/users", nil, withHost("api.example.com"), http.StatusOK, "/api/users")
+	if app.Routes()[0].Host != "" {
+		t.Fatalf("expected host route to be removed")
+	}
+}
+
+func TestServeHTTPAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items when the race detector is on")
+	}
+	app := goweb.New()
+	app.GET("/static", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "static")
+	})
+	app.GET("/users/{id}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("id"))
+	})
+	for _, path := range []string{"/static", "/users/4"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := newDiscardResponseWriter()
+		if allocs := testing.AllocsPerRun(100, func() { app.ServeHTTP(w, req) }); allocs != 0 {
+			t.Errorf("expected no allocations for %s; got %v", path, allocs)
+		}
+	}
+}
+
+func TestContextReuse(t *testing.T) {
+	app := goweb.New()
+	app.GET("/set", func(c *goweb.Context) goweb.Responder {
+		c.Set("user", "gopher")
+		return c.Text(http.StatusOK, c.Get("user").(string))
+	})
+	app.GET("/get/{id}", func(c *goweb.Context) goweb.Responder {
+		if c.Get("user") != nil {
+			t.Errorf("expected store to be reset")
+		}
+		return c.Text(http.StatusOK, c.Param("id")+c.Param("user"))
+	})
+	for i := 0; i < 10; i++ {
+		assert(t, app, "GET", "/set", nil, nil, http.StatusOK, "gopher")
+		assert(t, app, "GET", "/get/4", nil, nil, http.StatusOK, "4")
+	}
+}
+
+func TestMultipleResponsesOfOneKind(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		ok := c.Text(http.StatusOK, "ok")
+		c.Text(http.StatusTeapot, "unused")
+		return ok
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "ok")
+}
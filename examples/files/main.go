@@ -12,7 +12,7 @@ import (
 func main() {
 	app := goweb.New()
 
-	app.GET("/{path:.*}", func(c *goweb.Context) goweb.Responder {
+	app.GET("/{path...}", func(c *goweb.Context) goweb.Responder {
 		path := "assets/" + c.Param("path")
 		if path == "assets/" {
 			path = "assets/index.html"
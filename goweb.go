@@ -16,6 +16,18 @@ func New() *Engine {
 				"message": "Page Not Found",
 			})
 		},
+		methodNotAllowedHandler: func(c *Context) Responder {
+			return c.JSON(http.StatusMethodNotAllowed, Map{
+				"message": "Method Not Allowed",
+			})
+		},
+		notImplementedHandler: func(c *Context) Responder {
+			return c.JSON(http.StatusNotImplemented, Map{
+				"message": "Not Implemented",
+			})
+		},
+		autoHead:    true,
+		autoOptions: true,
 	}
 
 	return e
@@ -74,3 +74,15 @@ func assertPanic(t *testing.T, f func()) {
 	}()
 	f()
 }
+
+func assertHeader(t *testing.T, app *goweb.Engine, method string, path string, key string, value string) {
+	req, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	app.ServeHTTP(rr, req)
+	if got := rr.Header().Get(key); got != value {
+		t.Errorf("handler returned wrong %s header: got '%v' want '%v'", key, got, value)
+	}
+}
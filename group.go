@@ -0,0 +1,129 @@
+package goweb
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Group registers routes under a common path prefix. The
+// group's middleware chain is applied to each route in the
+// same order in which it was registered, after the chains
+// of the groups it is nested in.
+type Group struct {
+	host    *hostPattern
+	prefix  string
+	chain   []Handler
+	version string
+	engine  *Engine
+}
+
+// newGroup returns a group whose prefix is the given prefix
+// appended to the parent's. It panics if the prefix doesn't
+// start with '/'. The prefix "/" is the same as "".
+func newGroup(engine *Engine, host *hostPattern, parent string, prefix string, chain []Handler) *Group {
+	if prefix != "" && prefix[0] != '/' {
+		panic("group prefix '" + prefix + "' does not start with '/'")
+	}
+	return &Group{
+		host:   host,
+		prefix: parent + strings.TrimSuffix(prefix, "/"),
+		chain:  chain,
+		engine: engine,
+	}
+}
+
+// Group returns a new group nested in this group. Its
+// prefix is appended to this group's prefix, and its
+// middleware runs after this group's middleware.
+func (g *Group) Group(prefix string, middleware ...Handler) *Group {
+	nested := newGroup(g.engine, g.host, g.prefix, prefix, joinChains(g.chain, middleware))
+	nested.version = g.version
+	return nested
+}
+
+// path returns the path below the group's prefix. The empty
+// path below the root prefix is "/". It panics if the path
+// is not empty and doesn't start with '/'.
+func (g *Group) path(path string) string {
+	if path != "" && path[0] != '/' {
+		panic("group path '" + path + "' does not start with '/'")
+	}
+	if g.prefix+path == "" {
+		return "/"
+	}
+	return g.prefix + path
+}
+
+// Middleware returns a new group with the same prefix and
+// the given middleware appended to the chain.
+func (g *Group) Middleware(middleware ...Handler) *Group {
+	return &Group{
+		host:    g.host,
+		prefix:  g.prefix,
+		chain:   joinChains(g.chain, middleware),
+		version: g.version,
+		engine:  g.engine,
+	}
+}
+
+// Handle registers a route for the given method.
+func (g *Group) Handle(method string, path string, handler Handler) *Route {
+	rt := g.engine.newRoute(g.host, method, g.path(path), g.chain, handler)
+	if g.version != "" {
+		setVersion(g.engine, rt, g.version)
+	}
+	return g.engine.addRoute(rt)
+}
+
+// GET registers a route for method GET.
+func (g *Group) GET(path string, handler Handler) *Route {
+	return g.Handle(http.MethodGet, path, handler)
+}
+
+// PUT registers a route for method PUT.
+func (g *Group) PUT(path string, handler Handler) *Route {
+	return g.Handle(http.MethodPut, path, handler)
+}
+
+// POST registers a route for method POST.
+func (g *Group) POST(path string, handler Handler) *Route {
+	return g.Handle(http.MethodPost, path, handler)
+}
+
+// PATCH registers a route for method PATCH.
+func (g *Group) PATCH(path string, handler Handler) *Route {
+	return g.Handle(http.MethodPatch, path, handler)
+}
+
+// DELETE registers a route for method DELETE.
+func (g *Group) DELETE(path string, handler Handler) *Route {
+	return g.Handle(http.MethodDelete, path, handler)
+}
+
+// HEAD registers a route for method HEAD.
+func (g *Group) HEAD(path string, handler Handler) *Route {
+	return g.Handle(http.MethodHead, path, handler)
+}
+
+// OPTIONS registers a route for method OPTIONS.
+func (g *Group) OPTIONS(path string, handler Handler) *Route {
+	return g.Handle(http.MethodOptions, path, handler)
+}
+
+// Resource creates multiple REST handlers from given interface.
+func (g *Group) Resource(resourceName string, resource Resource) {
+	resourcePath := fmt.Sprintf("%s/{%s}", resourceName, resource.Identifier())
+	g.Handle(http.MethodGet, resourceName, resource.Index)
+	g.Handle(http.MethodGet, resourcePath, resource.Get)
+	g.Handle(http.MethodPut, resourcePath, resource.Put)
+	g.Handle(http.MethodDelete, resourcePath, resource.Delete)
+	g.Handle(http.MethodPost, resourceName, resource.Post)
+}
+
+// Remove removes the routes for the given method whose
+// pattern, below the group's prefix, matches the same paths
+// as the given pattern. See Engine.Remove.
+func (g *Group) Remove(method string, path string) bool {
+	return g.engine.removeRoutes(g.host, method, g.path(path))
+}
@@ -0,0 +1,145 @@
+package goweb_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func appendMiddleware(s string) goweb.Handler {
+	return func(c *goweb.Context) goweb.Responder {
+		prev, _ := c.Get("trace").(string)
+		c.Set("trace", prev+s)
+		return nil
+	}
+}
+
+func traceHandler(c *goweb.Context) goweb.Responder {
+	trace, _ := c.Get("trace").(string)
+	return c.Text(http.StatusOK, trace)
+}
+
+func TestGroup(t *testing.T) {
+	app := goweb.New()
+	api := app.Group("/api")
+	api.GET("/users/{id}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("id"))
+	})
+	assert(t, app, "GET", "/api/users/4", nil, nil, http.StatusOK, "4")
+	assert(t, app, "GET", "/users/4", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestGroupTrailingSlashPrefix(t *testing.T) {
+	app := goweb.New()
+	app.Group("/api/").GET("/users", traceHandler)
+	assert(t, app, "GET", "/api/users", nil, nil, http.StatusOK, "")
+}
+
+func TestGroupInvalidPrefix(t *testing.T) {
+	app := goweb.New()
+	assertPanic(t, func() {
+		app.Group("api")
+	})
+}
+
+func TestNestedGroupInvalidPrefix(t *testing.T) {
+	app := goweb.New()
+	api := app.Group("/api")
+	assertPanic(t, func() {
+		api.Group("v2")
+	})
+}
+
+func TestGroupInvalidPath(t *testing.T) {
+	app := goweb.New()
+	api := app.Group("/api")
+	assertPanic(t, func() {
+		api.GET("users", traceHandler)
+	})
+	assertPanic(t, func() {
+		api.Mount("static", http.NotFoundHandler())
+	})
+	assertPanic(t, func() {
+		api.Resource("todo", todoResource{})
+	})
+	assertPanic(t, func() {
+		api.Remove("GET", "users")
+	})
+}
+
+func TestRootGroup(t *testing.T) {
+	app := goweb.New()
+	root := app.Group("/")
+	root.GET("", traceHandler)
+	root.Group("/").GET("/users", traceHandler)
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "")
+	assert(t, app, "GET", "/users", nil, nil, http.StatusOK, "")
+	if !root.Remove("GET", "") {
+		t.Fatalf("expected root route to be removed")
+	}
+}
+
+func TestNestedGroups(t *testing.T) {
+	app := goweb.New()
+	api := app.Group("/api", appendMiddleware("a"))
+	v2 := api.Group("/v2", appendMiddleware("b"))
+	admin := v2.Group("/admin", appendMiddleware("c"), appendMiddleware("d"))
+	api.GET("/ping", traceHandler)
+	v2.GET("/ping", traceHandler)
+	admin.GET("/ping", traceHandler)
+	assert(t, app, "GET", "/api/ping", nil, nil, http.StatusOK, "a")
+	assert(t, app, "GET", "/api/v2/ping", nil, nil, http.StatusOK, "ab")
+	assert(t, app, "GET", "/api/v2/admin/ping", nil, nil, http.StatusOK, "abcd")
+}
+
+func TestSiblingGroupsDoNotShareChains(t *testing.T) {
+	app := goweb.New()
+	api := app.Group("/api", appendMiddleware("a"), appendMiddleware("b"))
+	users := api.Group("/users", appendMiddleware("u"))
+	posts := api.Group("/posts", appendMiddleware("p"))
+	users.GET("", traceHandler)
+	posts.GET("", traceHandler)
+	assert(t, app, "GET", "/api/users", nil, nil, http.StatusOK, "abu")
+	assert(t, app, "GET", "/api/posts", nil, nil, http.StatusOK, "abp")
+}
+
+func TestGroupMiddleware(t *testing.T) {
+	app := goweb.New()
+	api := app.Group("/api", appendMiddleware("a"))
+	api.Middleware(appendMiddleware("b")).GET("/b", traceHandler)
+	api.Middleware(appendMiddleware("c")).GET("/c", traceHandler)
+	assert(t, app, "GET", "/api/b", nil, nil, http.StatusOK, "ab")
+	assert(t, app, "GET", "/api/c", nil, nil, http.StatusOK, "ac")
+}
+
+func TestMiddlewareGroup(t *testing.T) {
+	app := goweb.New()
+	mw := app.Middleware(appendMiddleware("a"))
+	mw.Group("/admin", appendMiddleware("b")).GET("/", traceHandler)
+	assert(t, app, "GET", "/admin/", nil, nil, http.StatusOK, "ab")
+}
+
+func TestGroupMethods(t *testing.T) {
+	app := goweb.New()
+	g := app.Group("/g", appendMiddleware("a"))
+	g.GET("/", traceHandler)
+	g.PUT("/", traceHandler)
+	g.POST("/", traceHandler)
+	g.PATCH("/", traceHandler)
+	g.DELETE("/", traceHandler)
+	g.HEAD("/", traceHandler)
+	g.OPTIONS("/", traceHandler)
+	g.Handle("PURGE", "/", traceHandler)
+	for _, method := range []string{"GET", "PUT", "POST", "PATCH", "DELETE", "HEAD", "OPTIONS", "PURGE"} {
+		assert(t, app, method, "/g/", nil, nil, http.StatusOK, "a")
+	}
+}
+
+func TestGroupResource(t *testing.T) {
+	app := goweb.New()
+	app.Group("/api", appendMiddleware("a")).Resource("/todo", todoResource{})
+	assert(t, app, "GET", "/api/todo", nil, nil, http.StatusOK, "index")
+	assert(t, app, "GET", "/api/todo/1", nil, nil, http.StatusOK, "1")
+	assert(t, app, "POST", "/api/todo", nil, nil, http.StatusOK, "4")
+}
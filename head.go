@@ -0,0 +1,43 @@
+package goweb
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// headResponseWriter discards the body written by a GET
+// handler serving a HEAD request. The status is written
+// when the handler is done, so that Content-Length can be
+// set from the length of the discarded body.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	length int
+}
+
+func (w *headResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.length += len(b)
+	return len(b), nil
+}
+
+func (w *headResponseWriter) finish() {
+	if w.status == 0 {
+		return
+	}
+	h := w.ResponseWriter.Header()
+	if h.Get("Content-Length") == "" && bodyAllowed(w.status) {
+		h.Set("Content-Length", strconv.Itoa(w.length))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
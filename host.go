@@ -0,0 +1,103 @@
+package goweb
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// hostPattern is a compiled host pattern.
+type hostPattern struct {
+	pattern    string
+	parsed     *Pattern
+	static     string
+	part       routePart
+	paramNames []string
+	hasPort    bool
+}
+
+// newHostPattern compiles a host pattern. It panics if the
+// pattern is malformed. Host params without a regexp match
+// a single label of the host name.
+func newHostPattern(pattern string) *hostPattern {
+	p := &patternParser{pattern: pattern, host: true}
+	parsed, err := p.parse()
+	if err != nil {
+		panic(err)
+	}
+	labels := &Pattern{tokens: append([]patternToken(nil), parsed.tokens...)}
+	for i := range labels.tokens {
+		if labels.tokens[i].isParam() && labels.tokens[i].constraint == "" {
+			labels.tokens[i].constraint = `[^.]+`
+		}
+	}
+	h := &hostPattern{
+		pattern:    pattern,
+		parsed:     parsed,
+		part:       labels.regexpPart(0),
+		paramNames: parsed.Params(),
+	}
+	for _, t := range parsed.tokens {
+		if !t.isParam() && strings.Contains(t.literal, ":") {
+			h.hasPort = true
+		}
+	}
+	if len(h.paramNames) == 0 {
+		h.static = pattern
+	}
+	h.part.regexp = regexp.MustCompile("(?i)" + h.part.regexp.String())
+	return h
+}
+
+// match reports whether the host matches the pattern, and
+// appends the values of the host params to ps.
+func (h *hostPattern) match(host string, ps params) (params, bool) {
+	if !h.hasPort {
+		host = stripPort(host)
+	}
+	if h.static != "" {
+		return ps, strings.EqualFold(host, h.static)
+	}
+	matches := h.part.regexp.FindStringSubmatch(host)
+	if matches == nil {
+		return ps, false
+	}
+	for _, m := range matches[1:] {
+		ps = append(ps, param{value: m})
+	}
+	return ps, true
+}
+
+func stripPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// Host returns a group of routes that only match requests
+// for hosts matching the given pattern, such as
+// "api.example.com" or "{tenant}.example.com". Host params
+// are read with Context.Param like path params. Hosts are
+// matched case-insensitively, and the port of the request
+// host is ignored unless the pattern has one. Requests for
+// hosts without a matching route fall back to the routes
+// registered without a host.
+func (e *Engine) Host(pattern string) *Group {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, h := range e.hosts {
+		if h.pattern == pattern {
+			return newGroup(e, h, "", "", nil)
+		}
+	}
+	h := newHostPattern(pattern)
+	i := len(e.hosts)
+	for i > 0 && h.part.before(&e.hosts[i-1].part) {
+		i--
+	}
+	e.hosts = append(e.hosts, nil)
+	copy(e.hosts[i+1:], e.hosts[i:])
+	e.hosts[i] = h
+	return newGroup(e, h, "", "", nil)
+}
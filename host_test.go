@@ -0,0 +1,109 @@
+package goweb_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func withHost(host string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.Host = host
+	}
+}
+
+func TestHost(t *testing.T) {
+	app := goweb.New()
+	app.Host("api.example.com").GET("/users", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "api")
+	})
+	app.GET("/users", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "any")
+	})
+	assert(t, app, "GET", "/users", nil, withHost("api.example.com"), http.StatusOK, "api")
+	assert(t, app, "GET", "/users", nil, withHost("API.Example.com:8080"), http.StatusOK, "api")
+	assert(t, app, "GET", "/users", nil, withHost("www.example.com"), http.StatusOK, "any")
+}
+
+func TestHostParams(t *testing.T) {
+	app := goweb.New()
+	app.Host("{tenant}.example.com").GET("/users/{id}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("tenant")+" "+c.Param("id"))
+	})
+	assert(t, app, "GET", "/users/4", nil, withHost("acme.example.com"), http.StatusOK, "acme 4")
+	assert(t, app, "GET", "/users/4", nil, withHost("a.b.example.com"), http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestHostConstrainedParam(t *testing.T) {
+	app := goweb.New()
+	app.Host("v{version:[0-9]+}.example.com").GET("/", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("version"))
+	})
+	assert(t, app, "GET", "/", nil, withHost("v2.example.com"), http.StatusOK, "2")
+	assert(t, app, "GET", "/", nil, withHost("vx.example.com"), http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestHostWithPort(t *testing.T) {
+	app := goweb.New()
+	app.Host("localhost:8080").GET("/", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "8080")
+	})
+	assert(t, app, "GET", "/", nil, withHost("localhost:8080"), http.StatusOK, "8080")
+	assert(t, app, "GET", "/", nil, withHost("localhost:9090"), http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestHostSpecificity(t *testing.T) {
+	app := goweb.New()
+	app.Host("{tenant}.example.com").GET("/", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "tenant")
+	})
+	app.Host("www.example.com").GET("/", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "www")
+	})
+	assert(t, app, "GET", "/", nil, withHost("www.example.com"), http.StatusOK, "www")
+	assert(t, app, "GET", "/", nil, withHost("acme.example.com"), http.StatusOK, "tenant")
+}
+
+func TestHostGroup(t *testing.T) {
+	app := goweb.New()
+	api := app.Host("api.example.com").Group("/v1", appendMiddleware("a"))
+	api.GET("/users", traceHandler)
+	assert(t, app, "GET", "/v1/users", nil, withHost("api.example.com"), http.StatusOK, "a")
+	assert(t, app, "GET", "/v1/users", nil, withHost("example.com"), http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestHostMethodNotAllowed(t *testing.T) {
+	app := goweb.New()
+	app.Host("api.example.com").GET("/users", pathHandler)
+	app.POST("/users", pathHandler)
+	assert(t, app, "PUT", "/users", nil, withHost("api.example.com"), http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+	assert(t, app, "PUT", "/users", nil, withHost("www.example.com"), http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+}
+
+func TestHostParamClash(t *testing.T) {
+	app := goweb.New()
+	assertPanic(t, func() {
+		app.Host("{id}.example.com").GET("/users/{id}", pathHandler)
+	})
+}
+
+func TestHostMalformed(t *testing.T) {
+	app := goweb.New()
+	assertPanic(t, func() {
+		app.Host("{tenant.example.com")
+	})
+}
+
+func TestHostDuplicateValidate(t *testing.T) {
+	app := goweb.New()
+	app.Host("api.example.com").GET("/users", pathHandler)
+	app.GET("/users", pathHandler)
+	if err := app.Validate(); err != nil {
+		t.Fatalf("expected no conflicts; got %v", err)
+	}
+	app.Host("api.example.com").GET("/users", pathHandler)
+	if err := app.Validate(); err == nil {
+		t.Fatalf("expected a conflict")
+	}
+}
@@ -0,0 +1,120 @@
+package goweb
+
+import (
+	"net/http"
+	"strings"
+)
+
+// matcher is a condition on requests, besides the path,
+// that a route must match. The key describes the condition,
+// and is empty for conditions that can't be described.
+type matcher struct {
+	key   string
+	match func(*http.Request) bool
+}
+
+// Headers adds a matcher for the given header name value
+// pairs, so that the route only matches requests with each
+// header set to its value. An empty value matches any value
+// of the header. Matchers are checked after the path
+// matches, and if they don't match, the next route matching
+// the path is tried. It panics if given an odd number of
+// strings.
+func (r *Route) Headers(pairs ...string) *Route {
+	if len(pairs)%2 != 0 {
+		panic("route '" + r.route.pattern + "' given an odd number of header pairs")
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		name, value := http.CanonicalHeaderKey(pairs[i]), pairs[i+1]
+		r.addMatcher(matcher{
+			key: "header " + name + "=" + value,
+			match: func(req *http.Request) bool {
+				return matchValue(req.Header[name], value)
+			},
+		})
+	}
+	return r
+}
+
+// Queries adds a matcher for the given query param name
+// value pairs, so that the route only matches requests with
+// each query param set to its value. An empty value matches
+// any value of the query param. See Headers.
+func (r *Route) Queries(pairs ...string) *Route {
+	if len(pairs)%2 != 0 {
+		panic("route '" + r.route.pattern + "' given an odd number of query pairs")
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		name, value := pairs[i], pairs[i+1]
+		r.addMatcher(matcher{
+			key: "query " + name + "=" + value,
+			match: func(req *http.Request) bool {
+				return matchValue(req.URL.Query()[name], value)
+			},
+		})
+	}
+	return r
+}
+
+// Schemes adds a matcher so that the route only matches
+// requests with one of the given schemes, such as "https".
+// Requests received over TLS have the scheme "https", and
+// other requests "http", unless the request URL has a
+// scheme. See Headers.
+func (r *Route) Schemes(schemes ...string) *Route {
+	lower := make([]string, len(schemes))
+	for i := range schemes {
+		lower[i] = strings.ToLower(schemes[i])
+	}
+	return r.addMatcher(matcher{
+		key: "scheme " + strings.Join(lower, ","),
+		match: func(req *http.Request) bool {
+			return contains(lower, requestScheme(req))
+		},
+	})
+}
+
+// MatcherFunc adds a matcher so that the route only matches
+// requests for which f returns true. See Headers.
+func (r *Route) MatcherFunc(f func(*http.Request) bool) *Route {
+	return r.addMatcher(matcher{match: f})
+}
+
+func (r *Route) addMatcher(m matcher) *Route {
+	return r.update(func(rt *route) {
+		rt.matchers = append(rt.matchers[:len(rt.matchers):len(rt.matchers)], m)
+	})
+}
+
+// matchValue reports whether any of the values is the
+// given value, or whether there are any values if it is
+// empty.
+func matchValue(values []string, value string) bool {
+	if value == "" {
+		return len(values) > 0
+	}
+	return contains(values, value)
+}
+
+func requestScheme(r *http.Request) string {
+	if r.URL.Scheme != "" {
+		return strings.ToLower(r.URL.Scheme)
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// matcherKey returns a description of the route's matchers,
+// and whether all of them can be described.
+func (rt *route) matcherKey() (string, bool) {
+	keys := make([]string, len(rt.matchers))
+	for i, m := range rt.matchers {
+		if m.key == "" {
+			return "", false
+		}
+		keys[i] = m.key
+	}
+	return strings.Join(keys, " "), true
+}
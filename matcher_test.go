@@ -0,0 +1,101 @@
+package goweb_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func textHandler(text string) goweb.Handler {
+	return func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, text)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users", textHandler("v1"))
+	app.GET("/users", textHandler("v2")).Headers("x-api-version", "2")
+	app.GET("/users", textHandler("any")).Headers("X-Api-Version", "")
+	assert(t, app, "GET", "/users", nil, nil, http.StatusOK, "v1")
+	assert(t, app, "GET", "/users", nil, func(r *http.Request) {
+		r.Header.Set("X-Api-Version", "2")
+	}, http.StatusOK, "v2")
+	assert(t, app, "GET", "/users", nil, func(r *http.Request) {
+		r.Header.Set("X-Api-Version", "3")
+	}, http.StatusOK, "any")
+}
+
+func TestQueries(t *testing.T) {
+	app := goweb.New()
+	app.GET("/export", textHandler("csv")).Queries("format", "csv")
+	app.GET("/export", textHandler("json")).Queries("format", "json", "pretty", "")
+	assert(t, app, "GET", "/export?format=csv", nil, nil, http.StatusOK, "csv")
+	assert(t, app, "GET", "/export?format=json&pretty=1", nil, nil, http.StatusOK, "json")
+	assert(t, app, "GET", "/export?format=json", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestSchemes(t *testing.T) {
+	app := goweb.New()
+	app.GET("/login", textHandler("https")).Schemes("HTTPS")
+	app.GET("/login", textHandler("http"))
+	assert(t, app, "GET", "/login", nil, nil, http.StatusOK, "http")
+	assert(t, app, "GET", "/login", nil, func(r *http.Request) {
+		r.TLS = &tls.ConnectionState{}
+	}, http.StatusOK, "https")
+}
+
+func TestMatcherFunc(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", textHandler("beta")).MatcherFunc(func(r *http.Request) bool {
+		c, err := r.Cookie("beta")
+		return err == nil && c.Value == "1"
+	})
+	app.GET("/", textHandler("stable"))
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "stable")
+	assert(t, app, "GET", "/", nil, func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: "beta", Value: "1"})
+	}, http.StatusOK, "beta")
+}
+
+func TestMatcherFallsBackToLessSpecificRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/me", textHandler("me")).Headers("Authorization", "")
+	app.GET("/users/{id:[a-z]+}", textHandler("constrained")).Queries("full", "1")
+	app.GET("/users/{id}", textHandler("param"))
+	assert(t, app, "GET", "/users/me", nil, nil, http.StatusOK, "param")
+	assert(t, app, "GET", "/users/me?full=1", nil, nil, http.StatusOK, "constrained")
+	assert(t, app, "GET", "/users/me", nil, func(r *http.Request) {
+		r.Header.Set("Authorization", "token")
+	}, http.StatusOK, "me")
+}
+
+func TestMatcherMethodNotAllowed(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users", pathHandler).Headers("X-Api-Version", "2")
+	app.POST("/users", pathHandler)
+	assert(t, app, "GET", "/users", nil, nil, http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+}
+
+func TestMatcherValidate(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users", nopHandler)
+	app.GET("/users", nopHandler).Headers("X-Api-Version", "2")
+	app.GET("/users/{id}", nopHandler).MatcherFunc(func(*http.Request) bool { return true })
+	app.GET("/users/{name}", nopHandler).MatcherFunc(func(*http.Request) bool { return true })
+	assertConflicts(t, app)
+	app.GET("/users", nopHandler).Headers("x-api-version", "2")
+	assertConflicts(t, app, goweb.RouteConflict{Kind: goweb.ConflictDuplicate, Method: "GET", Pattern: "/users", Other: "/users"})
+}
+
+func TestMatcherRoutes(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", nopHandler).Headers("Accept", "text/csv").Schemes("https").MatcherFunc(func(*http.Request) bool { return true })
+	want := []string{"header Accept=text/csv", "scheme https", "func"}
+	if got := app.Routes()[0].Matchers; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected matchers: got %v want %v", got, want)
+	}
+}
@@ -13,9 +13,12 @@ type Middleware struct {
 	engine *Engine
 }
 
-func (m *Middleware) apply(handler Handler) Handler {
+func applyMiddleware(chain []Handler, handler Handler) Handler {
+	if len(chain) == 0 {
+		return handler
+	}
 	return func(c *Context) Responder {
-		for _, mw := range m.chain {
+		for _, mw := range chain {
 			if res := mw(c); res != nil {
 				return res
 			}
@@ -24,50 +27,74 @@ func (m *Middleware) apply(handler Handler) Handler {
 	}
 }
 
+// joinChains returns a new chain, so that chains derived
+// from the same parent never share a backing array.
+func joinChains(chain []Handler, middleware []Handler) []Handler {
+	joined := make([]Handler, 0, len(chain)+len(middleware))
+	joined = append(joined, chain...)
+	return append(joined, middleware...)
+}
+
 // Middleware returns a new middleware chain.
 func (m *Middleware) Middleware(middleware ...Handler) *Middleware {
 	return &Middleware{
-		chain:  append(m.chain, middleware...),
+		chain:  joinChains(m.chain, middleware),
 		engine: m.engine,
 	}
 }
 
+// Group returns a new group with the given prefix. The
+// group's middleware runs after this middleware chain.
+func (m *Middleware) Group(prefix string, middleware ...Handler) *Group {
+	return newGroup(m.engine, nil, "", prefix, joinChains(m.chain, middleware))
+}
+
+// Handle registers a route for the given method.
+func (m *Middleware) Handle(method string, path string, handler Handler) *Route {
+	return m.engine.registerRoute(nil, method, path, m.chain, handler)
+}
+
 // GET registers a route for method GET.
-func (m *Middleware) GET(path string, handler Handler) {
-	m.engine.GET(path, m.apply(handler))
+func (m *Middleware) GET(path string, handler Handler) *Route {
+	return m.engine.registerRoute(nil, http.MethodGet, path, m.chain, handler)
 }
 
 // PUT registers a route for method PUT.
-func (m *Middleware) PUT(path string, handler Handler) {
-	m.engine.PUT(path, m.apply(handler))
+func (m *Middleware) PUT(path string, handler Handler) *Route {
+	return m.engine.registerRoute(nil, http.MethodPut, path, m.chain, handler)
 }
 
 // POST registers a route for method POST.
-func (m *Middleware) POST(path string, handler Handler) {
-	m.engine.POST(path, m.apply(handler))
+func (m *Middleware) POST(path string, handler Handler) *Route {
+	return m.engine.registerRoute(nil, http.MethodPost, path, m.chain, handler)
 }
 
 // PATCH registers a route for method PATCH.
-func (m *Middleware) PATCH(path string, handler Handler) {
-	m.engine.PATCH(path, m.apply(handler))
+func (m *Middleware) PATCH(path string, handler Handler) *Route {
+	return m.engine.registerRoute(nil, http.MethodPatch, path, m.chain, handler)
 }
 
 // DELETE registers a route for method DELETE.
-func (m *Middleware) DELETE(path string, handler Handler) {
-	m.engine.DELETE(path, m.apply(handler))
+func (m *Middleware) DELETE(path string, handler Handler) *Route {
+	return m.engine.registerRoute(nil, http.MethodDelete, path, m.chain, handler)
 }
 
 // HEAD registers a route for method HEAD.
-func (m *Middleware) HEAD(path string, handler Handler) {
-	m.engine.HEAD(path, m.apply(handler))
+func (m *Middleware) HEAD(path string, handler Handler) *Route {
+	return m.engine.registerRoute(nil, http.MethodHead, path, m.chain, handler)
+}
+
+// OPTIONS registers a route for method OPTIONS.
+func (m *Middleware) OPTIONS(path string, handler Handler) *Route {
+	return m.engine.registerRoute(nil, http.MethodOptions, path, m.chain, handler)
 }
 
 // Resource creates multiple REST handlers from given interface.
 func (m *Middleware) Resource(resourceName string, resource Resource) {
 	resourcePath := fmt.Sprintf("%s/{%s}", resourceName, resource.Identifier())
-	m.engine.registerRoute(http.MethodGet, resourceName, m.apply(resource.Index))
-	m.engine.registerRoute(http.MethodGet, resourcePath, m.apply(resource.Get))
-	m.engine.registerRoute(http.MethodPut, resourcePath, m.apply(resource.Put))
-	m.engine.registerRoute(http.MethodDelete, resourcePath, m.apply(resource.Delete))
-	m.engine.registerRoute(http.MethodPost, resourceName, m.apply(resource.Post))
+	m.engine.registerRoute(nil, http.MethodGet, resourceName, m.chain, resource.Index)
+	m.engine.registerRoute(nil, http.MethodGet, resourcePath, m.chain, resource.Get)
+	m.engine.registerRoute(nil, http.MethodPut, resourcePath, m.chain, resource.Put)
+	m.engine.registerRoute(nil, http.MethodDelete, resourcePath, m.chain, resource.Delete)
+	m.engine.registerRoute(nil, http.MethodPost, resourceName, m.chain, resource.Post)
 }
@@ -1,7 +1,9 @@
 package goweb_test
 
 import (
+	"context"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/twharmon/goweb"
@@ -113,3 +115,97 @@ func TestChainMiddleware(t *testing.T) {
 	mw2.PUT("/", handler)
 	assert(t, app, "PUT", "/", nil, nil, http.StatusOK, "barbaz")
 }
+
+func TestHandleMiddleware(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Get("foo").(string))
+	}
+	app := goweb.New()
+	mw := app.Middleware(func(c *goweb.Context) goweb.Responder {
+		c.Set("foo", "bar")
+		return nil
+	})
+	mw.Handle("PURGE", "/", handler)
+	assert(t, app, "PURGE", "/", nil, nil, http.StatusOK, "bar")
+}
+
+func TestOPTIONSMiddleware(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Get("foo").(string))
+	}
+	app := goweb.New()
+	mw := app.Middleware(func(c *goweb.Context) goweb.Responder {
+		c.Set("foo", "bar")
+		return nil
+	})
+	mw.OPTIONS("/", handler)
+	assert(t, app, "OPTIONS", "/", nil, nil, http.StatusOK, "bar")
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+type ctxKey string
+
+func TestUseHTTP(t *testing.T) {
+	app := goweb.New()
+	var recorded int
+	app.UseHTTP(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), ctxKey("user"), "gopher")))
+			recorded = rec.status
+		})
+	})
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		if _, ok := c.ResponseWriter.(*statusRecorder); !ok {
+			t.Errorf("expected ResponseWriter to be wrapped")
+		}
+		return c.Text(http.StatusCreated, c.Request.Context().Value(ctxKey("user")).(string))
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusCreated, "gopher")
+	if recorded != http.StatusCreated {
+		t.Fatalf("expected middleware to record %d; got %d", http.StatusCreated, recorded)
+	}
+}
+
+func TestUseHTTPOrder(t *testing.T) {
+	app := goweb.New()
+	header := func(value string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Trace", value)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	app.UseHTTP(header("a"))
+	app.UseHTTP(header("b"), header("c"))
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, strings.Join(c.ResponseWriter.Header().Values("X-Trace"), ""))
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "abc")
+}
+
+func TestUseHTTPShortCircuit(t *testing.T) {
+	app := goweb.New()
+	app.UseHTTP(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	app.GET("/", pathHandler)
+	assert(t, app, "OPTIONS", "/missing", nil, nil, http.StatusNoContent, "")
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "/")
+}
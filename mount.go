@@ -0,0 +1,91 @@
+package goweb
+
+import (
+	"net/http"
+	"strings"
+)
+
+// mountParam is the name of the catch-all param holding the
+// path below the prefix of a mounted handler.
+const mountParam = "goweb-mount"
+
+// anyMethod is the method of the routes that pass requests
+// with a non-standard method, such as WebDAV's PROPFIND, to
+// mounted handlers.
+const anyMethod = "*"
+
+// mountMethods are the methods of the routes registered for
+// mounted handlers: every standard method, and anyMethod for
+// the others.
+var mountMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+	anyMethod,
+}
+
+// mount is a handler mounted under a path prefix.
+type mount struct {
+	prefix  string
+	handler http.Handler
+}
+
+// mount registers routes for every method that pass
+// requests for the prefix, and any path below it, to h.
+// Routes registered for paths below the prefix take
+// priority over the mounted handler.
+func (e *Engine) mount(host *hostPattern, prefix string, h http.Handler, chain []Handler) {
+	m := &mount{
+		prefix:  strings.TrimSuffix(prefix, "/"),
+		handler: h,
+	}
+	handler := mountHandler(h)
+	for _, method := range mountMethods {
+		var patterns []string
+		if m.prefix != "" {
+			patterns = append(patterns, m.prefix)
+		}
+		patterns = append(patterns, m.prefix+"/{"+mountParam+":.*}")
+		for _, pattern := range patterns {
+			rt := e.newRoute(host, method, pattern, chain, handler)
+			rt.mount = m
+			e.addRoute(rt)
+		}
+	}
+}
+
+// mountHandler returns a handler that calls h with the
+// prefix of the mount stripped from the request path.
+func mountHandler(h http.Handler) Handler {
+	return func(c *Context) Responder {
+		h.ServeHTTP(c.ResponseWriter, c.engine.withPath(c.Request, "/"+c.Param(mountParam)))
+		return nil
+	}
+}
+
+// Mount passes requests for the given path prefix, and any
+// path below it, to h, with the prefix stripped from the
+// request path. h may be another *Engine. It panics if the
+// prefix does not start with '/'.
+func (e *Engine) Mount(prefix string, h http.Handler) {
+	e.mount(nil, prefix, h, nil)
+}
+
+// Mount passes requests for the given path prefix below the
+// group's prefix to h. The group's middleware runs before h
+// is called. See Engine.Mount.
+func (g *Group) Mount(prefix string, h http.Handler) {
+	g.engine.mount(g.host, g.path(prefix), h, g.chain)
+}
+
+// Mount passes requests for the given path prefix to h
+// after running the middleware chain. See Engine.Mount.
+func (m *Middleware) Mount(prefix string, h http.Handler) {
+	m.engine.mount(nil, prefix, h, m.chain)
+}
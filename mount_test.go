@@ -0,0 +1,100 @@
+package goweb_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func TestMount(t *testing.T) {
+	app := goweb.New()
+	app.Mount("/static", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+	assert(t, app, "GET", "/static/css/main.css", nil, nil, http.StatusOK, "/css/main.css")
+	assert(t, app, "GET", "/static", nil, nil, http.StatusOK, "/")
+	assert(t, app, "GET", "/static/", nil, nil, http.StatusOK, "/")
+	assert(t, app, "POST", "/static/upload", nil, nil, http.StatusOK, "/upload")
+	assert(t, app, "GET", "/staticx", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestMountExtensionMethod(t *testing.T) {
+	app := goweb.New()
+	app.Mount("/dav", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+	app.GET("/other", pathHandler)
+	assert(t, app, "PROPFIND", "/dav/x", nil, nil, http.StatusOK, "PROPFIND /x")
+	assert(t, app, "MKCOL", "/dav", nil, nil, http.StatusOK, "MKCOL /")
+	assert(t, app, "PROPFIND", "/other", nil, nil, http.StatusNotImplemented, "{\"message\":\"Not Implemented\"}")
+	assertHeader(t, app, "DELETE", "/other", "Allow", "GET, HEAD, OPTIONS")
+}
+
+func TestMountEngine(t *testing.T) {
+	users := goweb.New()
+	users.GET("/{id}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, "user "+c.Param("id"))
+	})
+	app := goweb.New()
+	app.Mount("/users/", users)
+	assert(t, app, "GET", "/users/4", nil, nil, http.StatusOK, "user 4")
+	assert(t, app, "DELETE", "/users/4", nil, nil, http.StatusMethodNotAllowed, "{\"message\":\"Method Not Allowed\"}")
+}
+
+func TestMountRoutesTakePriority(t *testing.T) {
+	app := goweb.New()
+	app.Mount("/files", http.NotFoundHandler())
+	app.GET("/files/index", pathHandler)
+	assert(t, app, "GET", "/files/index", nil, nil, http.StatusOK, "/files/index")
+}
+
+func TestMountValidate(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", pathHandler)
+	app.GET("/static/index", pathHandler)
+	app.Mount("/", http.NotFoundHandler())
+	app.Mount("/static", http.NotFoundHandler())
+	if err := app.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	app.Mount("/static/", http.NotFoundHandler())
+	assertConflicts(t, app, goweb.RouteConflict{
+		Kind:    goweb.ConflictDuplicate,
+		Method:  "*",
+		Pattern: "/static",
+		Other:   "/static",
+	})
+}
+
+func TestGroupMount(t *testing.T) {
+	app := goweb.New()
+	api := app.Group("/api", appendMiddleware("a"))
+	api.Mount("/legacy", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+	assert(t, app, "GET", "/api/legacy/users", nil, nil, http.StatusOK, "/users")
+	api.Middleware(func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusUnauthorized, "unauthorized")
+	}).Mount("/admin", http.NotFoundHandler())
+	assert(t, app, "GET", "/api/admin/users", nil, nil, http.StatusUnauthorized, "unauthorized")
+}
+
+func TestMiddlewareMount(t *testing.T) {
+	app := goweb.New()
+	app.Middleware(func(c *goweb.Context) goweb.Responder {
+		c.ResponseWriter.Header().Set("X-Mounted", "yes")
+		return nil
+	}).Mount("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+	assert(t, app, "GET", "/a/b", nil, nil, http.StatusOK, "/a/b")
+	assertHeader(t, app, "GET", "/a/b", "X-Mounted", "yes")
+}
+
+func TestMountInvalidPrefix(t *testing.T) {
+	app := goweb.New()
+	assertPanic(t, func() {
+		app.Mount("static", http.NotFoundHandler())
+	})
+}
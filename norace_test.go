@@ -0,0 +1,5 @@
+//go:build !race
+
+package goweb_test
+
+const raceEnabled = false
@@ -1,5 +1,12 @@
 package goweb
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
 type param struct {
 	key   string
 	value string
@@ -8,10 +15,117 @@ type param struct {
 type params []param
 
 func (ps params) get(key string) string {
+	v, _ := ps.lookup(key)
+	return v
+}
+
+func (ps params) lookup(key string) (string, bool) {
 	for i := range ps {
 		if ps[i].key == key {
-			return ps[i].value
+			return ps[i].value, true
+		}
+	}
+	return "", false
+}
+
+// ParamType is the set of types that ParamAs parses path
+// params into.
+type ParamType interface {
+	string | int | int64 | uint | uint64 | float64 | bool | time.Time | time.Duration | UUID
+}
+
+// ErrParamNotFound is the error of a ParamError returned by
+// ParamAs for a param that doesn't exist.
+var ErrParamNotFound = errors.New("param not found")
+
+// ParamError is returned by ParamAs if a path param can't
+// be parsed.
+type ParamError struct {
+	Name  string
+	Value string
+	Type  string
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	if e.Err == ErrParamNotFound {
+		return fmt.Sprintf("param %s: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("param %s: can't parse %q as %s: %v", e.Name, e.Value, e.Type, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// ParamAs gets the path param by the given name, parsed as
+// a T. Numbers and bools are parsed with the strconv
+// package, durations with time.ParseDuration, times as
+// RFC 3339 or as a date (2006-01-02), and UUIDs with
+// ParseUUID. A *ParamError is returned if the param doesn't
+// exist, with ErrParamNotFound as its Err, or if its value
+// can't be parsed.
+func ParamAs[T ParamType](c *Context, name string) (T, error) {
+	var v T
+	s, ok := c.params.lookup(name)
+	if !ok {
+		return v, &ParamError{Name: name, Type: fmt.Sprintf("%T", v), Err: ErrParamNotFound}
+	}
+	var err error
+	switch p := interface{}(&v).(type) {
+	case *string:
+		*p = s
+	case *int:
+		*p, err = strconv.Atoi(s)
+	case *int64:
+		*p, err = strconv.ParseInt(s, 10, 64)
+	case *uint:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, 0)
+		*p = uint(u)
+	case *uint64:
+		*p, err = strconv.ParseUint(s, 10, 64)
+	case *float64:
+		*p, err = strconv.ParseFloat(s, 64)
+	case *bool:
+		*p, err = strconv.ParseBool(s)
+	case *time.Time:
+		*p, err = parseTime(s)
+	case *time.Duration:
+		*p, err = time.ParseDuration(s)
+	case *UUID:
+		*p, err = ParseUUID(s)
+	}
+	if err != nil {
+		var zero T
+		return zero, &ParamError{
+			Name:  name,
+			Value: s,
+			Type:  fmt.Sprintf("%T", v),
+			Err:   unwrapNumError(err),
 		}
 	}
-	return ""
+	return v, nil
+}
+
+// parseTime parses s as an RFC 3339 time or, failing that,
+// as a date.
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t, nil
+	}
+	if t, dateErr := time.Parse("2006-01-02", s); dateErr == nil {
+		return t, nil
+	}
+	return time.Time{}, err
+}
+
+// unwrapNumError returns the cause of a strconv error,
+// since ParamError already records the value.
+func unwrapNumError(err error) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		return ne.Err
+	}
+	return err
 }
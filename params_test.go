@@ -0,0 +1,84 @@
+package goweb_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/twharmon/goweb"
+)
+
+func paramAsHandler[T goweb.ParamType](c *goweb.Context) goweb.Responder {
+	v, err := goweb.ParamAs[T](c, "v")
+	if err != nil {
+		return c.Text(http.StatusBadRequest, err.Error())
+	}
+	return c.Text(http.StatusOK, fmt.Sprint(v))
+}
+
+func TestParamAs(t *testing.T) {
+	app := goweb.New()
+	app.GET("/string/{v}", paramAsHandler[string])
+	app.GET("/int/{v}", paramAsHandler[int])
+	app.GET("/int64/{v}", paramAsHandler[int64])
+	app.GET("/uint/{v}", paramAsHandler[uint])
+	app.GET("/uint64/{v}", paramAsHandler[uint64])
+	app.GET("/float64/{v}", paramAsHandler[float64])
+	app.GET("/bool/{v}", paramAsHandler[bool])
+	app.GET("/duration/{v}", paramAsHandler[time.Duration])
+	app.GET("/uuid/{v}", paramAsHandler[goweb.UUID])
+	app.GET("/time/{v}", func(c *goweb.Context) goweb.Responder {
+		v, err := goweb.ParamAs[time.Time](c, "v")
+		if err != nil {
+			return c.Text(http.StatusBadRequest, err.Error())
+		}
+		return c.Text(http.StatusOK, v.Format(time.RFC3339))
+	})
+	assert(t, app, "GET", "/string/abc", nil, nil, http.StatusOK, "abc")
+	assert(t, app, "GET", "/int/-12", nil, nil, http.StatusOK, "-12")
+	assert(t, app, "GET", "/int64/9000000000", nil, nil, http.StatusOK, "9000000000")
+	assert(t, app, "GET", "/uint/12", nil, nil, http.StatusOK, "12")
+	assert(t, app, "GET", "/uint64/18000000000000000000", nil, nil, http.StatusOK, "18000000000000000000")
+	assert(t, app, "GET", "/float64/1.5", nil, nil, http.StatusOK, "1.5")
+	assert(t, app, "GET", "/bool/true", nil, nil, http.StatusOK, "true")
+	assert(t, app, "GET", "/duration/1m30s", nil, nil, http.StatusOK, "1m30s")
+	assert(t, app, "GET", "/uuid/123e4567-e89b-12d3-a456-426614174000", nil, nil, http.StatusOK, "123e4567-e89b-12d3-a456-426614174000")
+	assert(t, app, "GET", "/time/2022-05-01T10:00:00Z", nil, nil, http.StatusOK, "2022-05-01T10:00:00Z")
+	assert(t, app, "GET", "/time/2022-05-01", nil, nil, http.StatusOK, "2022-05-01T00:00:00Z")
+	assert(t, app, "GET", "/int/abc", nil, nil, http.StatusBadRequest, `param v: can't parse "abc" as int: invalid syntax`)
+	assert(t, app, "GET", "/uint/-1", nil, nil, http.StatusBadRequest, `param v: can't parse "-1" as uint: invalid syntax`)
+	assert(t, app, "GET", "/uuid/abc", nil, nil, http.StatusBadRequest, `param v: can't parse "abc" as goweb.UUID: invalid UUID`)
+}
+
+func TestParamAsError(t *testing.T) {
+	app := goweb.New()
+	app.GET("/{id}", func(c *goweb.Context) goweb.Responder {
+		_, err := goweb.ParamAs[int](c, "id")
+		var pe *goweb.ParamError
+		if !errors.As(err, &pe) {
+			return c.Text(http.StatusInternalServerError, "not a ParamError")
+		}
+		if !errors.Is(err, strconv.ErrRange) {
+			return c.Text(http.StatusInternalServerError, "not a range error")
+		}
+		return c.Text(http.StatusBadRequest, pe.Name+" "+pe.Value+" "+pe.Type)
+	})
+	assert(t, app, "GET", "/99999999999999999999", nil, nil, http.StatusBadRequest, "id 99999999999999999999 int")
+}
+
+func TestParamAsMissing(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		if _, err := goweb.ParamAs[int](c, "id"); !errors.Is(err, goweb.ErrParamNotFound) {
+			return c.Text(http.StatusInternalServerError, "expected ErrParamNotFound")
+		}
+		if _, err := goweb.ParamAs[string](c, "id"); !errors.Is(err, goweb.ErrParamNotFound) {
+			return c.Text(http.StatusInternalServerError, "expected ErrParamNotFound")
+		}
+		return c.Text(http.StatusOK, "")
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "")
+}
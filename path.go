@@ -0,0 +1,277 @@
+package goweb
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// PathPolicy sets how a request is handled when its path
+// doesn't match a route, but a fixed version of the path
+// does.
+type PathPolicy int
+
+const (
+	// PathPolicyNone leaves the path as it is, so the request
+	// is not matched.
+	PathPolicyNone PathPolicy = iota
+
+	// PathPolicyRedirect redirects the request to the fixed
+	// path, keeping the query string. GET and HEAD requests
+	// are redirected with 301, and other methods with 308 so
+	// that the method and body are kept.
+	PathPolicyRedirect
+
+	// PathPolicyRewrite serves the request as if it was made
+	// for the fixed path.
+	PathPolicyRewrite
+)
+
+// CleanPath sets how requests for paths with repeated
+// slashes, or with "." or ".." segments, are handled when
+// the cleaned path matches a route. The default is
+// PathPolicyNone.
+func (e *Engine) CleanPath(policy PathPolicy) {
+	e.cleanPath = policy
+}
+
+// TrailingSlash sets how requests are handled when the
+// path doesn't match a route, but does with a trailing slash
+// added or removed. The default is PathPolicyNone.
+func (e *Engine) TrailingSlash(policy PathPolicy) {
+	e.trailingSlash = policy
+}
+
+// CaseInsensitive sets how requests are handled when the
+// path doesn't match a route, but does when static text is
+// matched case-insensitively. The fixed path has static text
+// cased as in the route's pattern, and param values as in
+// the request. The default is PathPolicyNone.
+func (e *Engine) CaseInsensitive(policy PathPolicy) {
+	e.caseInsensitive = policy
+}
+
+// UseRawPath sets whether routes are matched against the
+// escaped path of the request instead of the decoded one,
+// so that an escaped slash ("%2F") in a param value doesn't
+// split the value across segments. Param values are decoded
+// after matching. It is disabled by default.
+func (e *Engine) UseRawPath(enabled bool) {
+	e.rawPath = enabled
+}
+
+// routingPath returns the path of the request that routes
+// are matched against.
+func (e *Engine) routingPath(r *http.Request) string {
+	if !e.rawPath {
+		return r.URL.Path
+	}
+	return unescapeKeepSlash(r.URL.EscapedPath())
+}
+
+// unescapeKeepSlash decodes an escaped path, except for
+// escaped slashes and percent signs. Segments keep their
+// boundaries, and static parts of patterns still match
+// their decoded text.
+func unescapeKeepSlash(p string) string {
+	if strings.IndexByte(p, '%') < 0 {
+		return p
+	}
+	var b strings.Builder
+	b.Grow(len(p))
+	for i := 0; i < len(p); i++ {
+		if c, ok := unhexAt(p, i); ok && c != '/' && c != '%' {
+			b.WriteByte(c)
+			i += 2
+			continue
+		}
+		b.WriteByte(p[i])
+	}
+	return b.String()
+}
+
+// unescapeParam decodes the escaped slashes and percent
+// signs left in a param value by unescapeKeepSlash.
+func unescapeParam(v string) string {
+	if strings.IndexByte(v, '%') < 0 {
+		return v
+	}
+	var b strings.Builder
+	b.Grow(len(v))
+	for i := 0; i < len(v); i++ {
+		if c, ok := unhexAt(v, i); ok {
+			b.WriteByte(c)
+			i += 2
+			continue
+		}
+		b.WriteByte(v[i])
+	}
+	return b.String()
+}
+
+// unhexAt decodes the percent-encoded byte at index i of s.
+func unhexAt(s string, i int) (byte, bool) {
+	if s[i] != '%' || i+2 >= len(s) {
+		return 0, false
+	}
+	hi, ok1 := unhex(s[i+1])
+	lo, ok2 := unhex(s[i+2])
+	return hi<<4 | lo, ok1 && ok2
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// fixPath returns a cleaned version of the path, or one with
+// its trailing slash toggled or its case fixed, that matches
+// a route for the request, along with the policy for
+// handling it.
+func (e *Engine) fixPath(t *table, r *http.Request, p string) (string, PathPolicy) {
+	policy := PathPolicyNone
+	if e.cleanPath != PathPolicyNone {
+		if cleaned := cleanPath(p); cleaned != p {
+			if e.matches(t, r, cleaned) {
+				return cleaned, e.cleanPath
+			}
+			p = cleaned
+			policy = e.cleanPath
+		}
+	}
+	if fixed, ok := e.matchesFold(t, r, p); ok {
+		return fixed, combinePolicies(policy, e.caseInsensitive)
+	}
+	if e.trailingSlash != PathPolicyNone && p != "/" {
+		toggled := p + "/"
+		if strings.HasSuffix(p, "/") {
+			toggled = p[:len(p)-1]
+		}
+		policy = combinePolicies(policy, e.trailingSlash)
+		if e.matches(t, r, toggled) {
+			return toggled, policy
+		}
+		if fixed, ok := e.matchesFold(t, r, toggled); ok {
+			return fixed, combinePolicies(policy, e.caseInsensitive)
+		}
+	}
+	return "", PathPolicyNone
+}
+
+// combinePolicies returns the policy for a path fixed in
+// two ways. The request is redirected if either fix is a
+// redirect.
+func combinePolicies(a PathPolicy, b PathPolicy) PathPolicy {
+	if a == PathPolicyNone || b == PathPolicyRedirect {
+		return b
+	}
+	return a
+}
+
+// matchesFold returns the path matching a route for the
+// request with static text cased as in the route's
+// pattern, if CaseInsensitive is enabled.
+func (e *Engine) matchesFold(t *table, r *http.Request, p string) (string, bool) {
+	if e.caseInsensitive == PathPolicyNone {
+		return "", false
+	}
+	if fixed, ok := t.lookupFold(r, r.Method, p); ok {
+		return fixed, true
+	}
+	if r.Method == http.MethodHead && e.autoHead {
+		return t.lookupFold(r, http.MethodGet, p)
+	}
+	return "", false
+}
+
+// matches reports whether a route matches the request with
+// the given path, including GET routes for HEAD requests if
+// AutoHead is enabled.
+func (e *Engine) matches(t *table, r *http.Request, p string) bool {
+	if route, _ := t.lookup(r, r.Method, p, nil); route != nil {
+		return true
+	}
+	if r.Method == http.MethodHead && e.autoHead {
+		route, _ := t.lookup(r, http.MethodGet, p, nil)
+		return route != nil
+	}
+	return false
+}
+
+// cleanPath returns the shortest path equivalent to p, and
+// keeps its trailing slash.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+func redirectStatus(method string) int {
+	if method == http.MethodGet || method == http.MethodHead {
+		return http.StatusMovedPermanently
+	}
+	return http.StatusPermanentRedirect
+}
+
+// redirectLocation returns the URL for the given routing
+// path with the query string of the request. It returns
+// false if the URL starts with "//" or "/\", which browsers
+// read as a URL for another host.
+func (e *Engine) redirectLocation(r *http.Request, p string) (string, bool) {
+	u := url.URL{RawQuery: r.URL.RawQuery}
+	e.setPath(&u, p)
+	location := u.String()
+	if strings.HasPrefix(location, "//") || strings.HasPrefix(location, "/\\") {
+		return "", false
+	}
+	return location, true
+}
+
+// withPath returns a shallow copy of r with the given
+// routing path.
+func (e *Engine) withPath(r *http.Request, p string) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *r
+	u := *r.URL
+	e.setPath(&u, p)
+	r2.URL = &u
+	return r2
+}
+
+// setPath sets the path of u to the given routing path. If
+// UseRawPath is enabled, the escaped slashes and percent
+// signs left in the path by routingPath are kept escaped in
+// the URL's RawPath, so that segments keep their boundaries.
+func (e *Engine) setPath(u *url.URL, p string) {
+	if !e.rawPath || strings.IndexByte(p, '%') < 0 {
+		u.Path = p
+		u.RawPath = ""
+		return
+	}
+	u.Path = unescapeParam(p)
+	var b strings.Builder
+	start := 0
+	for i := 0; i < len(p); i++ {
+		if _, ok := unhexAt(p, i); ok {
+			b.WriteString(escapePath(p[start:i]))
+			b.WriteString(p[i : i+3])
+			i += 2
+			start = i + 1
+		}
+	}
+	b.WriteString(escapePath(p[start:]))
+	u.RawPath = b.String()
+}
@@ -0,0 +1,210 @@
+package goweb_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func pathHandler(c *goweb.Context) goweb.Responder {
+	return c.Text(http.StatusOK, c.Request.URL.Path)
+}
+
+func TestCleanPathNone(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users", pathHandler)
+	assert(t, app, "GET", "/x/../users", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestCleanPathRedirect(t *testing.T) {
+	app := goweb.New()
+	app.CleanPath(goweb.PathPolicyRedirect)
+	app.GET("/users/{id}", pathHandler)
+	app.POST("/users", pathHandler)
+	assertRedirect(t, app, "GET", "/users//../users/./4?a=b", http.StatusMovedPermanently, "/users/4?a=b")
+	assertRedirect(t, app, "HEAD", "/users//4", http.StatusMovedPermanently, "/users/4")
+	assertRedirect(t, app, "POST", "/x/../users", http.StatusPermanentRedirect, "/users")
+	assert(t, app, "GET", "/posts//", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestCleanPathRewrite(t *testing.T) {
+	app := goweb.New()
+	app.CleanPath(goweb.PathPolicyRewrite)
+	app.GET("/users/{id}", pathHandler)
+	assert(t, app, "GET", "/a/../users//4", nil, nil, http.StatusOK, "/users/4")
+}
+
+func TestTrailingSlashRedirect(t *testing.T) {
+	app := goweb.New()
+	app.TrailingSlash(goweb.PathPolicyRedirect)
+	app.GET("/users", pathHandler)
+	app.GET("/posts/", pathHandler)
+	app.PUT("/users/{id}", pathHandler)
+	assertRedirect(t, app, "GET", "/users/?page=2", http.StatusMovedPermanently, "/users?page=2")
+	assertRedirect(t, app, "GET", "/posts", http.StatusMovedPermanently, "/posts/")
+	assertRedirect(t, app, "PUT", "/users/4/", http.StatusPermanentRedirect, "/users/4")
+	assert(t, app, "GET", "/users", nil, nil, http.StatusOK, "/users")
+	assert(t, app, "GET", "/posts/", nil, nil, http.StatusOK, "/posts/")
+}
+
+func TestTrailingSlashRewrite(t *testing.T) {
+	app := goweb.New()
+	app.TrailingSlash(goweb.PathPolicyRewrite)
+	app.GET("/users", pathHandler)
+	assert(t, app, "GET", "/users/", nil, nil, http.StatusOK, "/users")
+}
+
+func TestCleanPathAndTrailingSlash(t *testing.T) {
+	app := goweb.New()
+	app.CleanPath(goweb.PathPolicyRewrite)
+	app.TrailingSlash(goweb.PathPolicyRedirect)
+	app.GET("/users", pathHandler)
+	assertRedirect(t, app, "GET", "/users/./", http.StatusMovedPermanently, "/users")
+}
+
+func assertRedirect(t *testing.T, app *goweb.Engine, method string, path string, status int, location string) {
+	assert(t, app, method, path, nil, nil, status, bodyFor(method, status, location))
+	assertHeader(t, app, method, path, "Location", location)
+}
+
+// bodyFor returns the body http.Redirect writes for the
+// given request method and status.
+func bodyFor(method string, status int, location string) string {
+	if method != http.MethodGet {
+		return ""
+	}
+	return "<a href=\"" + location + "\">" + http.StatusText(status) + "</a>."
+}
+
+func paramHandler(c *goweb.Context) goweb.Responder {
+	return c.Text(http.StatusOK, c.Param("name"))
+}
+
+func TestDecodedPathSplitsEscapedSlash(t *testing.T) {
+	app := goweb.New()
+	app.GET("/files/{name}", paramHandler)
+	assert(t, app, "GET", "/files/a%2Fb", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestRawPath(t *testing.T) {
+	app := goweb.New()
+	app.UseRawPath(true)
+	app.GET("/files/{name}", paramHandler)
+	app.GET("/café/{name}", paramHandler)
+	app.GET("/all/{name...}", paramHandler)
+	assert(t, app, "GET", "/files/a%2Fb", nil, nil, http.StatusOK, "a/b")
+	assert(t, app, "GET", "/files/a%2fb", nil, nil, http.StatusOK, "a/b")
+	assert(t, app, "GET", "/files/%E2%9C%93", nil, nil, http.StatusOK, "✓")
+	assert(t, app, "GET", "/files/c++", nil, nil, http.StatusOK, "c++")
+	assert(t, app, "GET", "/files/c%2B%2B", nil, nil, http.StatusOK, "c++")
+	assert(t, app, "GET", "/files/100%25", nil, nil, http.StatusOK, "100%")
+	assert(t, app, "GET", "/files/%252F", nil, nil, http.StatusOK, "%2F")
+	assert(t, app, "GET", "/caf%C3%A9/x", nil, nil, http.StatusOK, "x")
+	assert(t, app, "GET", "/all/a%2Fb/c", nil, nil, http.StatusOK, "a/b/c")
+	assert(t, app, "GET", "/files/a/b", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestRawPathTrailingSlashRedirect(t *testing.T) {
+	app := goweb.New()
+	app.UseRawPath(true)
+	app.TrailingSlash(goweb.PathPolicyRedirect)
+	app.GET("/files/{name}", paramHandler)
+	assertHeader(t, app, "GET", "/files/a%2Fb%20c/?x=1", "Location", "/files/a%2Fb%20c?x=1")
+}
+
+func TestRawPathTrailingSlashRewrite(t *testing.T) {
+	app := goweb.New()
+	app.UseRawPath(true)
+	app.TrailingSlash(goweb.PathPolicyRewrite)
+	app.GET("/files/{name}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Request.URL.EscapedPath()+" "+c.Param("name"))
+	})
+	assert(t, app, "GET", "/files/a%2Fb/", nil, nil, http.StatusOK, "/files/a%2Fb a/b")
+}
+
+func TestRawPathMount(t *testing.T) {
+	sub := goweb.New()
+	sub.UseRawPath(true)
+	sub.GET("/{name}", paramHandler)
+	app := goweb.New()
+	app.UseRawPath(true)
+	app.Mount("/files", sub)
+	assert(t, app, "GET", "/files/a%2Fb", nil, nil, http.StatusOK, "a/b")
+	assert(t, app, "GET", "/files/100%25", nil, nil, http.StatusOK, "100%")
+}
+
+func TestCaseSensitiveByDefault(t *testing.T) {
+	app := goweb.New()
+	app.GET("/products/{id}", pathHandler)
+	assert(t, app, "GET", "/Products/ABC", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestCaseInsensitiveRewrite(t *testing.T) {
+	app := goweb.New()
+	app.CaseInsensitive(goweb.PathPolicyRewrite)
+	app.GET("/products/{id}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Request.URL.Path+" "+c.Param("id"))
+	})
+	app.GET("/files/{name}.JSON", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Request.URL.Path)
+	})
+	app.GET("/static/{path...}", pathHandler)
+	assert(t, app, "GET", "/Products/ABC", nil, nil, http.StatusOK, "/products/ABC ABC")
+	assert(t, app, "GET", "/FILES/Report.json", nil, nil, http.StatusOK, "/files/Report.JSON")
+	assert(t, app, "GET", "/Static/CSS/Main.css", nil, nil, http.StatusOK, "/static/CSS/Main.css")
+}
+
+func TestCaseInsensitiveRedirect(t *testing.T) {
+	app := goweb.New()
+	app.CaseInsensitive(goweb.PathPolicyRedirect)
+	app.GET("/products/{id:[A-Z]+}", pathHandler)
+	app.POST("/orders", pathHandler)
+	assertRedirect(t, app, "GET", "/Products/ABC?ref=email", http.StatusMovedPermanently, "/products/ABC?ref=email")
+	assertRedirect(t, app, "HEAD", "/PRODUCTS/ABC", http.StatusMovedPermanently, "/products/ABC")
+	assertRedirect(t, app, "POST", "/Orders", http.StatusPermanentRedirect, "/orders")
+	assert(t, app, "GET", "/Products/abc", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+// withRawPath sets the path of the request, which
+// httptest.NewRequest would read as a host if it starts with
+// "//".
+func withRawPath(p string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.URL.Host = ""
+		r.URL.Path = p
+		r.URL.RawPath = ""
+	}
+}
+
+func TestRedirectToOtherHost(t *testing.T) {
+	notFound := "{\"message\":\"Page Not Found\"}"
+	app := goweb.New()
+	app.TrailingSlash(goweb.PathPolicyRedirect)
+	app.GET("/{a}/{b}", pathHandler)
+	assert(t, app, "GET", "/", nil, withRawPath("//evil.com/"), http.StatusNotFound, notFound)
+	assertRedirect(t, app, "GET", "/a/b/", http.StatusMovedPermanently, "/a/b")
+
+	app = goweb.New()
+	app.CaseInsensitive(goweb.PathPolicyRedirect)
+	app.GET("/{a}/evil.com", pathHandler)
+	assert(t, app, "GET", "/", nil, withRawPath("//EVIL.COM"), http.StatusNotFound, notFound)
+	assertRedirect(t, app, "GET", "/a/EVIL.COM", http.StatusMovedPermanently, "/a/evil.com")
+}
+
+func TestCaseInsensitivePrefersExactCase(t *testing.T) {
+	app := goweb.New()
+	app.CaseInsensitive(goweb.PathPolicyRewrite)
+	app.GET("/About", textHandler("upper"))
+	app.GET("/about", textHandler("lower"))
+	assert(t, app, "GET", "/About", nil, nil, http.StatusOK, "upper")
+	assert(t, app, "GET", "/about", nil, nil, http.StatusOK, "lower")
+}
+
+func TestCaseInsensitiveAndTrailingSlash(t *testing.T) {
+	app := goweb.New()
+	app.CaseInsensitive(goweb.PathPolicyRewrite)
+	app.TrailingSlash(goweb.PathPolicyRedirect)
+	app.GET("/products", pathHandler)
+	assertRedirect(t, app, "GET", "/Products/", http.StatusMovedPermanently, "/products")
+}
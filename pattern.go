@@ -0,0 +1,429 @@
+package goweb
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// Pattern is a parsed route pattern. A pattern is a path
+// starting with '/', in which {name} matches a param value
+// within a path segment, {name:regexp} matches a param
+// value with the given regexp, and a final {name...}
+// matches the rest of the path, including slashes.
+type Pattern struct {
+	raw    string
+	tokens []patternToken
+}
+
+// patternToken is either literal text or a param of a
+// pattern. The constraint of a param never contains capture
+// groups.
+type patternToken struct {
+	literal    string
+	name       string
+	constraint string
+	offset     int
+}
+
+func (t *patternToken) isParam() bool {
+	return t.name != ""
+}
+
+// PatternError describes a malformed route pattern.
+type PatternError struct {
+	// Pattern is the malformed pattern.
+	Pattern string
+
+	// Offset is the byte offset in the pattern where the
+	// problem was found.
+	Offset int
+
+	// Message describes the problem.
+	Message string
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("pattern '%s': %s at offset %d", e.Pattern, e.Message, e.Offset)
+}
+
+// Syntax is a syntax of route patterns.
+type Syntax int
+
+const (
+	// SyntaxBraces is the default syntax, in which params
+	// are written as {name}, {name:regexp} or {name...}.
+	SyntaxBraces Syntax = iota
+
+	// SyntaxColons accepts :name for a param matching a path
+	// segment and a final *name for a param matching the
+	// rest of the path, as in gin and httprouter, as well as
+	// the params of SyntaxBraces.
+	SyntaxColons
+)
+
+// catchAllConstraint is the regexp of a param matching the
+// rest of the path.
+const catchAllConstraint = ".*"
+
+// ParsePattern parses a route pattern. Capture groups in
+// param regexps are made non-capturing, so that they can't
+// shift the values of other params. A *PatternError is
+// returned if the pattern is malformed.
+func ParsePattern(pattern string) (*Pattern, error) {
+	return ParsePatternSyntax(pattern, SyntaxBraces)
+}
+
+// ParsePatternSyntax parses a route pattern written in the
+// given syntax. See ParsePattern.
+func ParsePatternSyntax(pattern string, syntax Syntax) (*Pattern, error) {
+	p := &patternParser{pattern: pattern, syntax: syntax}
+	return p.parse()
+}
+
+type patternParser struct {
+	pattern string
+	pos     int
+	tokens  []patternToken
+	host    bool
+	syntax  Syntax
+}
+
+func (p *patternParser) errorf(offset int, format string, args ...interface{}) error {
+	return &PatternError{
+		Pattern: p.pattern,
+		Offset:  offset,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *patternParser) parse() (*Pattern, error) {
+	if p.host && p.pattern == "" {
+		return nil, p.errorf(0, "empty host")
+	}
+	if !p.host && (p.pattern == "" || p.pattern[0] != '/') {
+		return nil, p.errorf(0, "does not start with '/'")
+	}
+	start := 0
+	for p.pos < len(p.pattern) {
+		switch p.pattern[p.pos] {
+		case '{':
+			if start < p.pos {
+				p.tokens = append(p.tokens, patternToken{literal: p.pattern[start:p.pos], offset: start})
+			}
+			if err := p.parseParam(); err != nil {
+				return nil, err
+			}
+			start = p.pos
+		case '}':
+			return nil, p.errorf(p.pos, "unmatched '}'")
+		case ':', '*':
+			if p.syntax != SyntaxColons {
+				p.pos++
+				continue
+			}
+			if start < p.pos {
+				p.tokens = append(p.tokens, patternToken{literal: p.pattern[start:p.pos], offset: start})
+			}
+			if err := p.parseColonParam(); err != nil {
+				return nil, err
+			}
+			start = p.pos
+		default:
+			p.pos++
+		}
+	}
+	if start < p.pos {
+		p.tokens = append(p.tokens, patternToken{literal: p.pattern[start:p.pos], offset: start})
+	}
+	return &Pattern{
+		raw:    p.pattern,
+		tokens: p.tokens,
+	}, nil
+}
+
+// parseParam parses a param starting at the '{' at the
+// current position, and leaves the position after its
+// closing '}'.
+func (p *patternParser) parseParam() error {
+	open := p.pos
+	p.pos++
+	nameStart := p.pos
+	for p.pos < len(p.pattern) && isParamNameByte(p.pattern[p.pos]) {
+		p.pos++
+	}
+	name := p.pattern[nameStart:p.pos]
+	if p.pos == len(p.pattern) {
+		return p.errorf(open, "unclosed '{'")
+	}
+	if name == "" {
+		return p.errorf(nameStart, "missing param name")
+	}
+	if err := p.checkDuplicate(open, name); err != nil {
+		return err
+	}
+	token := patternToken{name: name, offset: open}
+	switch p.pattern[p.pos] {
+	case '}':
+		p.pos++
+	case '.':
+		if !strings.HasPrefix(p.pattern[p.pos:], "...}") {
+			return p.errorf(p.pos, "invalid character '.' in param name")
+		}
+		p.pos += len("...}")
+		if p.pos != len(p.pattern) {
+			return p.errorf(open, "catch-all param '%s' is not at the end of the pattern", name)
+		}
+		token.constraint = catchAllConstraint
+	case ':':
+		p.pos++
+		constraint, err := p.parseConstraint(open)
+		if err != nil {
+			return err
+		}
+		token.constraint = constraint
+	default:
+		return p.errorf(p.pos, "invalid character '%c' in param name", p.pattern[p.pos])
+	}
+	p.tokens = append(p.tokens, token)
+	return nil
+}
+
+// parseColonParam parses a :name or *name param starting at
+// the current position. The name ends at the first byte
+// that can't be part of a name.
+func (p *patternParser) parseColonParam() error {
+	open := p.pos
+	p.pos++
+	for p.pos < len(p.pattern) && isParamNameByte(p.pattern[p.pos]) {
+		p.pos++
+	}
+	name := p.pattern[open+1 : p.pos]
+	if name == "" {
+		return p.errorf(open+1, "missing param name")
+	}
+	if err := p.checkDuplicate(open, name); err != nil {
+		return err
+	}
+	token := patternToken{name: name, offset: open}
+	if p.pattern[open] == '*' {
+		if p.pos != len(p.pattern) {
+			return p.errorf(open, "catch-all param '%s' is not at the end of the pattern", name)
+		}
+		token.constraint = catchAllConstraint
+	}
+	p.tokens = append(p.tokens, token)
+	return nil
+}
+
+func (p *patternParser) checkDuplicate(offset int, name string) error {
+	for i := range p.tokens {
+		if p.tokens[i].name == name {
+			return p.errorf(offset, "duplicate param name '%s'", name)
+		}
+	}
+	return nil
+}
+
+// parseConstraint parses the regexp of a param up to the
+// '}' that closes the param. Braces in the regexp must be
+// balanced, unless they are escaped or in a character
+// class.
+func (p *patternParser) parseConstraint(open int) (string, error) {
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.pattern) {
+		switch p.pattern[p.pos] {
+		case '\\':
+			p.pos++
+		case '[':
+			p.skipCharClass()
+			continue
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				constraint := p.pattern[start:p.pos]
+				p.pos++
+				if constraint == "" {
+					return "", p.errorf(start, "empty regexp")
+				}
+				return p.compileConstraint(constraint, start)
+			}
+			depth--
+		}
+		p.pos++
+	}
+	return "", p.errorf(open, "unclosed '{'")
+}
+
+// skipCharClass moves the position past the character
+// class starting at the current position. A ']' right after
+// the opening '[' or '[^' is part of the class.
+func (p *patternParser) skipCharClass() {
+	p.pos++
+	if p.pos < len(p.pattern) && p.pattern[p.pos] == '^' {
+		p.pos++
+	}
+	if p.pos < len(p.pattern) && p.pattern[p.pos] == ']' {
+		p.pos++
+	}
+	for p.pos < len(p.pattern) {
+		switch p.pattern[p.pos] {
+		case '\\':
+			p.pos++
+		case ']':
+			p.pos++
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *patternParser) compileConstraint(constraint string, offset int) (string, error) {
+	re, err := syntax.Parse(constraint, syntax.Perl)
+	if err != nil {
+		return "", p.errorf(offset, "invalid regexp: %v", err)
+	}
+	if re.MaxCap() == 0 {
+		return constraint, nil
+	}
+	removeCaptures(re)
+	return re.String(), nil
+}
+
+// removeCaptures replaces every capture group in re with
+// the expression it captures.
+func removeCaptures(re *syntax.Regexp) {
+	for re.Op == syntax.OpCapture {
+		*re = *re.Sub[0]
+	}
+	for _, sub := range re.Sub {
+		removeCaptures(sub)
+	}
+}
+
+func isParamNameByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '-' || b == '_'
+}
+
+// String returns the pattern as it was given to
+// ParsePattern.
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// Params returns the names of the params in the pattern in
+// the order in which they appear.
+func (p *Pattern) Params() []string {
+	var names []string
+	for i := range p.tokens {
+		if p.tokens[i].isParam() {
+			names = append(names, p.tokens[i].name)
+		}
+	}
+	return names
+}
+
+// Constraint returns the regexp of the param with the given
+// name, with capture groups made non-capturing. An empty
+// string is returned if the param has no regexp or doesn't
+// exist.
+func (p *Pattern) Constraint(name string) string {
+	for i := range p.tokens {
+		if p.tokens[i].name == name {
+			return p.tokens[i].constraint
+		}
+	}
+	return ""
+}
+
+// regexpPart returns a route part matching the part of a
+// path matched by the tokens starting at the given index,
+// with a capture group for each param. Its fold regexp
+// matches literal text case-insensitively.
+func (p *Pattern) regexpPart(from int) routePart {
+	part := routePart{kind: routePartRegexp, tokens: p.tokens[from:]}
+	for _, t := range part.tokens {
+		part.rank = append(part.rank, t.rank())
+	}
+	part.key = (&Pattern{tokens: part.tokens}).canonical()
+	var b, fold strings.Builder
+	b.WriteString("^")
+	fold.WriteString("^")
+	for _, t := range part.tokens {
+		if !t.isParam() {
+			b.WriteString(regexp.QuoteMeta(t.literal))
+			fold.WriteString("(?i:" + regexp.QuoteMeta(t.literal) + ")")
+			continue
+		}
+		b.WriteString("(" + t.paramRegexp() + ")")
+		fold.WriteString("(" + t.paramRegexp() + ")")
+	}
+	b.WriteString("$")
+	fold.WriteString("$")
+	part.regexp = regexp.MustCompile(b.String())
+	part.foldRegexp = regexp.MustCompile(fold.String())
+	return part
+}
+
+// rank returns how specific the token is. Literal text is
+// the most specific, then params with a regexp, then params
+// without one, and params whose regexp can match a '/' are
+// the least specific.
+func (t *patternToken) rank() int {
+	switch {
+	case !t.isParam():
+		return rankLiteral
+	case t.constraint == "":
+		return rankUnconstrained
+	case spansSegments(t.constraint):
+		return rankSpanning
+	}
+	return rankConstrained
+}
+
+// spansSegments reports whether the given regexp can match
+// a '/'.
+func spansSegments(constraint string) bool {
+	re, err := syntax.Parse(constraint, syntax.Perl)
+	if err != nil {
+		return true
+	}
+	return matchesSlash(re)
+}
+
+func matchesSlash(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return true
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if r == '/' {
+				return true
+			}
+		}
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if re.Rune[i] <= '/' && '/' <= re.Rune[i+1] {
+				return true
+			}
+		}
+	}
+	for _, sub := range re.Sub {
+		if matchesSlash(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// paramRegexp returns the regexp matching values of the
+// param.
+func (t *patternToken) paramRegexp() string {
+	if t.constraint == "" {
+		return `[^\/]*`
+	}
+	return t.constraint
+}
@@ -0,0 +1,149 @@
+package goweb_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func assertPatternError(t *testing.T, pattern string, offset int) {
+	assertPatternSyntaxError(t, pattern, goweb.SyntaxBraces, offset)
+}
+
+func assertPatternSyntaxError(t *testing.T, pattern string, syntax goweb.Syntax, offset int) {
+	_, err := goweb.ParsePatternSyntax(pattern, syntax)
+	var perr *goweb.PatternError
+	if !errors.As(err, &perr) {
+		t.Errorf("expected *PatternError for '%s'; got %v", pattern, err)
+		return
+	}
+	if perr.Offset != offset {
+		t.Errorf("wrong offset for '%s': got %d want %d (%v)", pattern, perr.Offset, offset, err)
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	p, err := goweb.ParsePattern("/users/{id:[0-9]+}/posts/{slug}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Params(); !reflect.DeepEqual(got, []string{"id", "slug"}) {
+		t.Errorf("wrong params: got %v", got)
+	}
+	equals(t, p.Constraint("id"), "[0-9]+")
+	equals(t, p.Constraint("slug"), "")
+	equals(t, p.String(), "/users/{id:[0-9]+}/posts/{slug}")
+}
+
+func TestParsePatternBracesInRegexp(t *testing.T) {
+	p, err := goweb.ParsePattern(`/{code:[0-9]{3}}/{sym:[}{]}/{esc:a\}b}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equals(t, p.Constraint("code"), "[0-9]{3}")
+	equals(t, p.Constraint("sym"), "[}{]")
+	equals(t, p.Constraint("esc"), `a\}b`)
+}
+
+func TestParsePatternCaptureGroups(t *testing.T) {
+	p, err := goweb.ParsePattern("/{kind:(a|b)(?P<n>[0-9])}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equals(t, p.Constraint("kind"), "[ab][0-9]")
+}
+
+func TestParsePatternErrors(t *testing.T) {
+	assertPatternError(t, "", 0)
+	assertPatternError(t, "foo", 0)
+	assertPatternError(t, "/a/{b", 3)
+	assertPatternError(t, "/a/{b:[0-9]+", 3)
+	assertPatternError(t, "/a/b}", 4)
+	assertPatternError(t, "/a/{}", 4)
+	assertPatternError(t, "/a/{b c}", 5)
+	assertPatternError(t, "/{id}/{id}", 6)
+	assertPatternError(t, "/{id:}", 5)
+	assertPatternError(t, "/{id:[0-9}", 1)
+	assertPatternError(t, "/{id:(a}", 5)
+	assertPatternError(t, "/{path...}/x", 1)
+	assertPatternError(t, "/{a.b}", 3)
+}
+
+func TestCatchAllParamRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/files/{path...}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("path"))
+	})
+	assert(t, app, "GET", "/files/css/main.css", nil, nil, http.StatusOK, "css/main.css")
+	assert(t, app, "GET", "/files/", nil, nil, http.StatusOK, "")
+	assert(t, app, "GET", "/files", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestColonSyntax(t *testing.T) {
+	handler := func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("id")+" "+c.Param("format")+" "+c.Param("path"))
+	}
+	app := goweb.New()
+	app.PatternSyntax(goweb.SyntaxColons)
+	app.GET("/users/:id", handler)
+	app.GET("/posts/:id.:format", handler)
+	app.GET("/comments/{id:[0-9]+}", handler)
+	app.GET("/files/*path", handler)
+	assert(t, app, "GET", "/users/4", nil, nil, http.StatusOK, "4  ")
+	assert(t, app, "GET", "/posts/4.json", nil, nil, http.StatusOK, "4 json ")
+	assert(t, app, "GET", "/comments/4", nil, nil, http.StatusOK, "4  ")
+	assert(t, app, "GET", "/files/a/b", nil, nil, http.StatusOK, "  a/b")
+}
+
+func TestColonSyntaxErrors(t *testing.T) {
+	assertPatternSyntaxError(t, "/a/:", goweb.SyntaxColons, 4)
+	assertPatternSyntaxError(t, "/*path/x", goweb.SyntaxColons, 1)
+	assertPatternSyntaxError(t, "/:id/:id", goweb.SyntaxColons, 5)
+}
+
+func TestColonIsLiteralInBraceSyntax(t *testing.T) {
+	app := goweb.New()
+	app.GET("/a:b/*", pathHandler)
+	assert(t, app, "GET", "/a:b/*", nil, nil, http.StatusOK, "/a:b/*")
+}
+
+func TestSyntaxesAreEquivalent(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id}", nopHandler)
+	app.GET("/files/{path:.*}", nopHandler)
+	app.PatternSyntax(goweb.SyntaxColons)
+	app.GET("/users/:name", nopHandler)
+	app.GET("/files/*rest", nopHandler)
+	app.GET("/files/{name...}", nopHandler)
+	assertConflicts(t, app,
+		goweb.RouteConflict{Kind: goweb.ConflictDuplicate, Method: "GET", Pattern: "/users/:name", Other: "/users/{id}"},
+		goweb.RouteConflict{Kind: goweb.ConflictDuplicate, Method: "GET", Pattern: "/files/*rest", Other: "/files/{path:.*}"},
+		goweb.RouteConflict{Kind: goweb.ConflictDuplicate, Method: "GET", Pattern: "/files/{name...}", Other: "/files/{path:.*}"},
+	)
+}
+
+func TestCaptureGroupParamRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/{kind:(a|b)}/{id:([0-9]+)}/{rest}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("kind")+" "+c.Param("id")+" "+c.Param("rest"))
+	})
+	assert(t, app, "GET", "/b/12/x", nil, nil, http.StatusOK, "b 12 x")
+}
+
+func TestAdjacentParamRoute(t *testing.T) {
+	app := goweb.New()
+	app.GET("/{a:[a-z]+}{b:[0-9]+}", func(c *goweb.Context) goweb.Responder {
+		return c.Text(http.StatusOK, c.Param("a")+" "+c.Param("b"))
+	})
+	assert(t, app, "GET", "/abc123", nil, nil, http.StatusOK, "abc 123")
+}
+
+func TestMalformedPatternPanics(t *testing.T) {
+	app := goweb.New()
+	assertPanic(t, func() {
+		app.GET("/{id}/{id}", nopHandler)
+	})
+}
@@ -0,0 +1,8 @@
+//go:build race
+
+package goweb_test
+
+// raceEnabled is set when the race detector is on. It makes
+// sync.Pool drop items at random, so allocations can't be
+// counted.
+const raceEnabled = true
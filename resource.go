@@ -8,11 +8,11 @@ import (
 // Resource creates multiple REST handlers from given interface.
 func (e *Engine) Resource(resourceName string, resource Resource) {
 	resourcePath := fmt.Sprintf("%s/{%s}", resourceName, resource.Identifier())
-	e.registerRoute(http.MethodGet, resourceName, resource.Index)
-	e.registerRoute(http.MethodGet, resourcePath, resource.Get)
-	e.registerRoute(http.MethodPut, resourcePath, resource.Put)
-	e.registerRoute(http.MethodDelete, resourcePath, resource.Delete)
-	e.registerRoute(http.MethodPost, resourceName, resource.Post)
+	e.registerRoute(nil, http.MethodGet, resourceName, nil, resource.Index)
+	e.registerRoute(nil, http.MethodGet, resourcePath, nil, resource.Get)
+	e.registerRoute(nil, http.MethodPut, resourcePath, nil, resource.Put)
+	e.registerRoute(nil, http.MethodDelete, resourcePath, nil, resource.Delete)
+	e.registerRoute(nil, http.MethodPost, resourceName, nil, resource.Post)
 }
 
 // Resource handles Index, Get, Put, Delete, and Post requests.
@@ -2,6 +2,7 @@ package goweb
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 )
 
@@ -11,6 +12,13 @@ const (
 	contentTypeTextPlain       = "text/plain; charset=utf-8"
 )
 
+// Content-Type header values are assigned directly to the
+// header map to avoid allocating a new slice per response.
+var (
+	contentTypeApplicationJSONValue = []string{contentTypeApplicationJSON}
+	contentTypeTextPlainValue       = []string{contentTypeTextPlain}
+)
+
 // JSONResponse implements Responder interface.
 type JSONResponse struct {
 	context *Context
@@ -43,6 +51,7 @@ type RedirectResponse struct {
 	context *Context
 	url     string
 	status  int
+	err     error
 }
 
 // Responder is the Responder interface that responds to
@@ -53,7 +62,7 @@ type Responder interface {
 
 // Respond sends a JSON response.
 func (r *JSONResponse) Respond() {
-	r.context.ResponseWriter.Header().Set(contentTypeHeader, contentTypeApplicationJSON)
+	r.context.ResponseWriter.Header()[contentTypeHeader] = contentTypeApplicationJSONValue
 	r.context.ResponseWriter.WriteHeader(r.status)
 	json.NewEncoder(r.context.ResponseWriter).Encode(r.body)
 }
@@ -68,12 +77,19 @@ func (r *NilResponse) Respond() {}
 
 // Respond sends a plain text response.
 func (r *TextResponse) Respond() {
-	r.context.ResponseWriter.Header().Set(contentTypeHeader, contentTypeTextPlain)
+	r.context.ResponseWriter.Header()[contentTypeHeader] = contentTypeTextPlainValue
 	r.context.ResponseWriter.WriteHeader(r.status)
-	r.context.ResponseWriter.Write([]byte(r.body))
+	io.WriteString(r.context.ResponseWriter, r.body)
 }
 
-// Respond sends a plain text response.
+// Respond sends a redirect response.
 func (r *RedirectResponse) Respond() {
+	if r.err != nil {
+		r.context.LogError(r.err)
+		r.context.JSON(http.StatusInternalServerError, Map{
+			"message": "Internal Server Error",
+		}).Respond()
+		return
+	}
 	http.Redirect(r.context.ResponseWriter, r.context.Request, r.url, r.status)
 }
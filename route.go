@@ -1,12 +1,91 @@
 package goweb
 
 import (
+	"net/http"
 	"regexp"
 )
 
 type route struct {
-	regexp     *regexp.Regexp
 	handler    Handler
 	paramNames []string
 	method     string
+	pattern    string
+	parsed     *Pattern
+	parts      []routePart
+	host       *hostPattern
+	mount      *mount
+	middleware int
+	matchers   []matcher
+	version    string
+	name       string
+	reverse    []reversePart
+}
+
+// matches reports whether every matcher of the route
+// matches the request. A nil request only matches routes
+// without matchers.
+func (rt *route) matches(r *http.Request) bool {
+	if r == nil {
+		return len(rt.matchers) == 0
+	}
+	for _, m := range rt.matchers {
+		if !m.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (rt *route) lastPart() *routePart {
+	return &rt.parts[len(rt.parts)-1]
+}
+
+type routePartKind int
+
+const (
+	routePartStatic routePartKind = iota
+	routePartParam
+	routePartCatchAll
+	routePartRegexp
+)
+
+// routePart is one step of a route's path in the routing
+// tree. A route is a sequence of static and param parts,
+// optionally ending in a catch-all, or in a regexp that
+// matches the rest of the path when the remaining pattern
+// can't be expressed in the tree.
+type routePart struct {
+	kind       routePartKind
+	static     string
+	regexp     *regexp.Regexp
+	foldRegexp *regexp.Regexp
+	tokens     []patternToken
+	rank       []int
+	key        string
+}
+
+// Ranks of pattern tokens, from most to least specific.
+const (
+	rankLiteral = iota
+	rankConstrained
+	rankUnconstrained
+	rankSpanning
+)
+
+// before reports whether the regexp part p is more specific
+// than q. Parts are compared by the ranks of their tokens in
+// order, and a part with more tokens is more specific than
+// one whose ranks it starts with. Equally ranked parts are
+// ordered by their canonical pattern, so that the order
+// never depends on the order of registration.
+func (p *routePart) before(q *routePart) bool {
+	for i := 0; i < len(p.rank) && i < len(q.rank); i++ {
+		if p.rank[i] != q.rank[i] {
+			return p.rank[i] < q.rank[i]
+		}
+	}
+	if len(p.rank) != len(q.rank) {
+		return len(p.rank) > len(q.rank)
+	}
+	return p.key < q.key
 }
@@ -0,0 +1,160 @@
+package goweb
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"text/tabwriter"
+)
+
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	// Method is the request method of the route, or "*" for
+	// a handler mounted with Mount.
+	Method string `json:"method"`
+
+	// Host is the host pattern of the route, or empty if it
+	// matches any host.
+	Host string `json:"host,omitempty"`
+
+	// Pattern is the path pattern of the route, including
+	// the prefixes of the groups it was registered in.
+	Pattern string `json:"pattern"`
+
+	// Params are the names of the host and path params of
+	// the route.
+	Params []string `json:"params,omitempty"`
+
+	// Constraints are the regexps of the params that have
+	// one, by param name.
+	Constraints map[string]string `json:"constraints,omitempty"`
+
+	// Matchers describe the conditions besides the path
+	// that requests must match, such as "header X-Api=2" or
+	// "func" for a MatcherFunc.
+	Matchers []string `json:"matchers,omitempty"`
+
+	// Name is the name of the route, if it has one.
+	Name string `json:"name,omitempty"`
+
+	// Middleware is the number of middleware handlers run
+	// before the route's handler.
+	Middleware int `json:"middleware"`
+}
+
+// Routes returns a description of every registered route in
+// the order in which they were registered. A mounted
+// handler is listed once with method "*" and its prefix as
+// the pattern, unless it is an *Engine, in which case its
+// routes are listed with the prefix prepended.
+func (e *Engine) Routes() []RouteInfo {
+	var routes []RouteInfo
+	seen := make(map[*mount]bool)
+	for _, rt := range e.load().routes {
+		if rt.mount == nil {
+			routes = append(routes, rt.info())
+			continue
+		}
+		if seen[rt.mount] {
+			continue
+		}
+		seen[rt.mount] = true
+		routes = append(routes, rt.mountInfo()...)
+	}
+	return routes
+}
+
+func (rt *route) info() RouteInfo {
+	info := RouteInfo{
+		Method:     rt.method,
+		Pattern:    rt.pattern,
+		Params:     append([]string(nil), rt.paramNames...),
+		Name:       rt.name,
+		Middleware: rt.middleware,
+	}
+	for _, m := range rt.matchers {
+		key := m.key
+		if key == "" {
+			key = "func"
+		}
+		info.Matchers = append(info.Matchers, key)
+	}
+	patterns := []*Pattern{rt.parsed}
+	if rt.host != nil {
+		info.Host = rt.host.pattern
+		patterns = append(patterns, rt.host.parsed)
+	}
+	for _, p := range patterns {
+		for _, name := range p.Params() {
+			if constraint := p.Constraint(name); constraint != "" {
+				if info.Constraints == nil {
+					info.Constraints = make(map[string]string)
+				}
+				info.Constraints[name] = constraint
+			}
+		}
+	}
+	return info
+}
+
+// mountInfo describes the mount of the route, which is one
+// of the routes registered for the mount.
+func (rt *route) mountInfo() []RouteInfo {
+	outer := rt.info()
+	outer.Method = "*"
+	outer.Pattern = rt.mount.prefix
+	if outer.Pattern == "" {
+		outer.Pattern = "/"
+	}
+	if n := len(outer.Params); n > 0 && outer.Params[n-1] == mountParam {
+		outer.Params = outer.Params[:n-1]
+	}
+	if len(outer.Params) == 0 {
+		outer.Params = nil
+	}
+	delete(outer.Constraints, mountParam)
+	if len(outer.Constraints) == 0 {
+		outer.Constraints = nil
+	}
+	sub, ok := rt.mount.handler.(*Engine)
+	if !ok {
+		return []RouteInfo{outer}
+	}
+	routes := sub.Routes()
+	for i := range routes {
+		r := &routes[i]
+		r.Pattern = rt.mount.prefix + r.Pattern
+		if r.Host == "" {
+			r.Host = outer.Host
+		}
+		r.Params = append(outer.Params[:len(outer.Params):len(outer.Params)], r.Params...)
+		for name, constraint := range outer.Constraints {
+			if r.Constraints == nil {
+				r.Constraints = make(map[string]string)
+			}
+			r.Constraints[name] = constraint
+		}
+		r.Middleware += outer.Middleware
+	}
+	return routes
+}
+
+// PrintRoutes writes a table of the registered routes to w,
+// with one line per route.
+func (e *Engine) PrintRoutes(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tROUTE\tNAME\tMIDDLEWARE")
+	for _, r := range e.Routes() {
+		fmt.Fprintf(tw, "%s\t%s%s\t%s\t%d\n", r.Method, r.Host, r.Pattern, r.Name, r.Middleware)
+	}
+	return tw.Flush()
+}
+
+// RoutesHandler responds with the registered routes as
+// JSON. It is not registered by default; register it to
+// expose the routes on a debug endpoint:
+//
+//	app.GET("/debug/routes", app.RoutesHandler)
+func (e *Engine) RoutesHandler(c *Context) Responder {
+	return c.JSON(http.StatusOK, e.Routes())
+}
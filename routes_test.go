@@ -0,0 +1,79 @@
+package goweb_test
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func TestRoutes(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id:[0-9]+}", nopHandler).Name("user")
+	api := app.Host("{tenant}.example.com").Group("/api", appendMiddleware("a"))
+	api.Middleware(appendMiddleware("b")).POST("/posts/{slug}", nopHandler)
+	want := []goweb.RouteInfo{
+		{
+			Method:      "GET",
+			Pattern:     "/users/{id:[0-9]+}",
+			Params:      []string{"id"},
+			Constraints: map[string]string{"id": "[0-9]+"},
+			Name:        "user",
+		},
+		{
+			Method:     "POST",
+			Host:       "{tenant}.example.com",
+			Pattern:    "/api/posts/{slug}",
+			Params:     []string{"tenant", "slug"},
+			Middleware: 2,
+		},
+	}
+	if got := app.Routes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected routes:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestRoutesMount(t *testing.T) {
+	users := goweb.New()
+	users.GET("/{id}", nopHandler)
+	users.Middleware(appendMiddleware("a")).DELETE("/{id}", nopHandler)
+	app := goweb.New()
+	app.Mount("/static", http.NotFoundHandler())
+	app.Group("/users", appendMiddleware("b")).Mount("/", users)
+	want := []goweb.RouteInfo{
+		{Method: "*", Pattern: "/static"},
+		{Method: "GET", Pattern: "/users/{id}", Params: []string{"id"}, Middleware: 1},
+		{Method: "DELETE", Pattern: "/users/{id}", Params: []string{"id"}, Middleware: 2},
+	}
+	if got := app.Routes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected routes:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestPrintRoutes(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", nopHandler).Name("home")
+	app.Middleware(appendMiddleware("a")).POST("/users/{id}", nopHandler)
+	var b bytes.Buffer
+	if err := app.PrintRoutes(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"METHOD  ROUTE        NAME  MIDDLEWARE",
+		"GET     /            home  0",
+		"POST    /users/{id}        1",
+		"",
+	}, "\n")
+	if b.String() != want {
+		t.Fatalf("unexpected table:\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestRoutesHandler(t *testing.T) {
+	app := goweb.New()
+	app.GET("/debug/routes", app.RoutesHandler)
+	assert(t, app, "GET", "/debug/routes", nil, nil, http.StatusOK, "[{\"method\":\"GET\",\"pattern\":\"/debug/routes\",\"middleware\":0}]")
+}
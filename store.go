@@ -0,0 +1,51 @@
+package goweb
+
+// Key is a typed key for values in the Context data store.
+// Keys are compared by identity, so values set with a Key
+// never collide with values set by other packages, even if
+// they use the same name.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new Key for values of type T. The name
+// is only used to describe the key.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+func (k *Key[T]) storeKey() {}
+
+// storeKey is implemented by every Key, so that
+// Context.Value can tell them from other keys.
+type storeKey interface {
+	storeKey()
+}
+
+// Set sets the value for the key in the Context data store.
+func (k *Key[T]) Set(c *Context, value T) {
+	if c.store == nil {
+		c.store = make(map[interface{}]interface{})
+	}
+	c.store[k] = value
+}
+
+// Get gets the value for the key from the Context data
+// store. The zero value and false are returned if the value
+// isn't set.
+func (k *Key[T]) Get(c *Context) (T, bool) {
+	v, ok := c.store[k].(T)
+	return v, ok
+}
+
+// GetAs gets a value set with Context.Set as a T. The zero
+// value and false are returned if the value isn't set or
+// isn't a T.
+func GetAs[T any](c *Context, key string) (T, bool) {
+	v, ok := c.store[key].(T)
+	return v, ok
+}
@@ -0,0 +1,54 @@
+package goweb_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func TestGetAs(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		c.Set("user", "gopher")
+		user, ok := goweb.GetAs[string](c, "user")
+		if !ok {
+			return c.Text(http.StatusInternalServerError, "not set")
+		}
+		if _, ok := goweb.GetAs[int](c, "user"); ok {
+			return c.Text(http.StatusInternalServerError, "wrong type")
+		}
+		if _, ok := goweb.GetAs[string](c, "missing"); ok {
+			return c.Text(http.StatusInternalServerError, "missing")
+		}
+		return c.Text(http.StatusOK, user)
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "gopher")
+}
+
+func TestKey(t *testing.T) {
+	type user struct {
+		name string
+	}
+	userKey := goweb.NewKey[*user]("user")
+	otherKey := goweb.NewKey[*user]("user")
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		userKey.Set(c, &user{name: "gopher"})
+		if _, ok := otherKey.Get(c); ok {
+			return c.Text(http.StatusInternalServerError, "keys collide")
+		}
+		if c.Get("user") != nil {
+			return c.Text(http.StatusInternalServerError, "key collides with string")
+		}
+		if _, ok := c.Value(userKey).(*user); !ok {
+			return c.Text(http.StatusInternalServerError, "not a context value")
+		}
+		u, ok := userKey.Get(c)
+		if !ok {
+			return c.Text(http.StatusInternalServerError, "not set")
+		}
+		return c.Text(http.StatusOK, u.name+" "+userKey.String())
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "gopher user")
+}
@@ -0,0 +1,188 @@
+package goweb
+
+import "net/http"
+
+// table is a snapshot of the registered routes, used to
+// route requests. A table is never modified once it is
+// built, so requests can be routed with it while routes are
+// registered or removed.
+type table struct {
+	routes    []*route
+	trees     map[string]*node
+	hosts     []hostTable
+	names     map[string]*route
+	maxParams int
+}
+
+// hostTable holds the route trees for a host pattern.
+type hostTable struct {
+	host  *hostPattern
+	trees map[string]*node
+}
+
+// invalidate discards the current table, so that the next
+// request builds a new one. It must be called with e.mu
+// held.
+func (e *Engine) invalidate() {
+	e.table.Store((*table)(nil))
+}
+
+// load returns the current table, building it if the
+// registry changed since it was last built.
+func (e *Engine) load() *table {
+	if t, _ := e.table.Load().(*table); t != nil {
+		return t
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if t, _ := e.table.Load().(*table); t != nil {
+		return t
+	}
+	t := e.build()
+	e.table.Store(t)
+	return t
+}
+
+// build builds a table from the registry. It must be called
+// with e.mu held.
+func (e *Engine) build() *table {
+	t := &table{
+		routes: e.routes,
+		trees:  make(map[string]*node),
+		names:  make(map[string]*route),
+	}
+	hostTrees := make(map[*hostPattern]map[string]*node)
+	for _, rt := range e.routes {
+		trees := t.trees
+		if rt.host != nil {
+			trees = hostTrees[rt.host]
+			if trees == nil {
+				trees = make(map[string]*node)
+				hostTrees[rt.host] = trees
+			}
+		}
+		root := trees[rt.method]
+		if root == nil {
+			root = new(node)
+			trees[rt.method] = root
+		}
+		root.insert(rt)
+		if rt.name != "" {
+			t.names[rt.name] = rt
+		}
+		if len(rt.paramNames) > t.maxParams {
+			t.maxParams = len(rt.paramNames)
+		}
+	}
+	for _, h := range e.hosts {
+		if trees := hostTrees[h]; trees != nil {
+			t.hosts = append(t.hosts, hostTable{host: h, trees: trees})
+		}
+	}
+	return t
+}
+
+// treesFor returns the route trees for the given host, or
+// for any host if it is nil.
+func (t *table) treesFor(host *hostPattern) map[string]*node {
+	if host == nil {
+		return t.trees
+	}
+	for _, h := range t.hosts {
+		if h.host == host {
+			return h.trees
+		}
+	}
+	return nil
+}
+
+// lookup finds the route for the request with the given
+// method and path. Routes for host patterns matching the
+// request host are tried first, in order of specificity,
+// and then routes for any host. Param values are stored in
+// buf if it has enough capacity.
+func (t *table) lookup(r *http.Request, method string, path string, buf params) (*route, params) {
+	if cap(buf) < t.maxParams {
+		buf = make(params, 0, t.maxParams)
+	}
+	for _, h := range t.hosts {
+		ps, ok := h.host.match(r.Host, buf[:0])
+		if !ok {
+			continue
+		}
+		if route, ps := lookupTree(h.trees[method], path, ps, r); route != nil {
+			return route, ps
+		}
+	}
+	return lookupTree(t.trees[method], path, buf[:0], r)
+}
+
+// lookupFold is like lookup, but matches static text
+// case-insensitively. It returns the path with static text
+// cased as in the pattern of the route found.
+func (t *table) lookupFold(r *http.Request, method string, path string) (string, bool) {
+	for _, h := range t.hosts {
+		if _, ok := h.host.match(r.Host, nil); !ok || h.trees[method] == nil {
+			continue
+		}
+		if route, buf := h.trees[method].lookupFold(path, nil, r); route != nil {
+			return string(buf), true
+		}
+	}
+	if root := t.trees[method]; root != nil {
+		if route, buf := root.lookupFold(path, nil, r); route != nil {
+			return string(buf), true
+		}
+	}
+	return "", false
+}
+
+func lookupTree(routes *node, path string, ps params, r *http.Request) (*route, params) {
+	if routes == nil {
+		return nil, nil
+	}
+	route, ps := routes.lookup(path, ps, r)
+	if route == nil {
+		return nil, nil
+	}
+	if len(ps) > len(route.paramNames) {
+		ps = ps[:len(route.paramNames)]
+	}
+	for i := range ps {
+		ps[i].key = route.paramNames[i]
+	}
+	return route, ps
+}
+
+// hasMethod reports whether any route is registered for the
+// method.
+func (t *table) hasMethod(method string) bool {
+	if t.trees[method] != nil {
+		return true
+	}
+	for _, h := range t.hosts {
+		if h.trees[method] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// methods returns every method with a registered route,
+// other than anyMethod.
+func (t *table) methods() []string {
+	var methods []string
+	for method := range t.trees {
+		if method != anyMethod {
+			methods = append(methods, method)
+		}
+	}
+	for _, h := range t.hosts {
+		for method := range h.trees {
+			if method != anyMethod && !contains(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
@@ -0,0 +1,66 @@
+package goweb_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func TestConcurrentRegistration(t *testing.T) {
+	app := goweb.New()
+	app.GET("/stable/{id}", pathHandler)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", fmt.Sprintf("/stable/%d", j), nil)
+				app.ServeHTTP(w, r)
+				if w.Code != http.StatusOK {
+					t.Errorf("expected stable route to be found; got %d", w.Code)
+					return
+				}
+				r, _ = http.NewRequest("GET", fmt.Sprintf("/dynamic/%d", j), nil)
+				app.ServeHTTP(httptest.NewRecorder(), r)
+				app.Routes()
+				app.URL("dynamic-1")
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 200; j++ {
+			path := fmt.Sprintf("/dynamic/%d", j)
+			app.GET(path, pathHandler).Name(fmt.Sprintf("dynamic-%d", j))
+			app.Host(fmt.Sprintf("h%d.example.com", j)).GET(path, pathHandler)
+			if j%2 == 0 {
+				app.Remove("GET", path)
+			}
+		}
+	}()
+	wg.Wait()
+	if err := app.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, app, "GET", "/dynamic/1", nil, nil, http.StatusOK, "/dynamic/1")
+	assert(t, app, "GET", "/dynamic/2", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestRemoveDuringRequest(t *testing.T) {
+	app := goweb.New()
+	app.GET("/once", func(c *goweb.Context) goweb.Responder {
+		app.Remove("GET", "/once")
+		app.GET("/next", pathHandler)
+		return c.Text(http.StatusOK, "once")
+	})
+	assert(t, app, "GET", "/once", nil, nil, http.StatusOK, "once")
+	assert(t, app, "GET", "/once", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+	assert(t, app, "GET", "/next", nil, nil, http.StatusOK, "/next")
+}
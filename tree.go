@@ -0,0 +1,262 @@
+package goweb
+
+import (
+	"net/http"
+	"strings"
+)
+
+// node is a node of a radix tree of routes. Static parts of
+// route paths share common prefixes, and each node may have
+// a param child, routes matched by regexp, and catch-all
+// routes. Regexp routes are kept sorted by specificity, and
+// those that can match across segments are kept apart, as
+// they are less specific than the param child. Routes with
+// the same pattern are kept in the order in which they are
+// tried, with routes that have matchers first.
+type node struct {
+	path        string
+	indices     []byte
+	children    []*node
+	param       *node
+	regexps     []*route
+	spanRegexps []*route
+	catchAlls   []*route
+	routes      []*route
+}
+
+func (n *node) insert(rt *route) {
+	for _, p := range rt.parts {
+		switch p.kind {
+		case routePartStatic:
+			n = n.insertStatic(p.static)
+		case routePartParam:
+			if n.param == nil {
+				n.param = new(node)
+			}
+			n = n.param
+		case routePartCatchAll:
+			n.catchAlls = insertRoute(n.catchAlls, rt)
+			return
+		case routePartRegexp:
+			if p.rank[0] == rankSpanning {
+				n.spanRegexps = insertSorted(n.spanRegexps, rt)
+			} else {
+				n.regexps = insertSorted(n.regexps, rt)
+			}
+			return
+		}
+	}
+	n.routes = insertRoute(n.routes, rt)
+}
+
+// insertRoute inserts rt into routes with the same pattern,
+// after every route with matchers if it has any, or at the
+// end otherwise.
+func insertRoute(routes []*route, rt *route) []*route {
+	i := len(routes)
+	for i > 0 && len(rt.matchers) > 0 && len(routes[i-1].matchers) == 0 {
+		i--
+	}
+	return insertAt(routes, i, rt)
+}
+
+// insertSorted inserts rt into routes after every route
+// whose regexp part is more specific, or equally specific
+// with matchers if rt has none.
+func insertSorted(routes []*route, rt *route) []*route {
+	p := rt.lastPart()
+	i := len(routes)
+	for i > 0 {
+		q := routes[i-1].lastPart()
+		if !p.before(q) && (q.before(p) || len(rt.matchers) == 0 || len(routes[i-1].matchers) > 0) {
+			break
+		}
+		i--
+	}
+	return insertAt(routes, i, rt)
+}
+
+func insertAt(routes []*route, i int, rt *route) []*route {
+	routes = append(routes, nil)
+	copy(routes[i+1:], routes[i:])
+	routes[i] = rt
+	return routes
+}
+
+// insertStatic inserts the given static path below n,
+// splitting nodes where needed, and returns the node at
+// which the path ends.
+func (n *node) insertStatic(path string) *node {
+	for path != "" {
+		i := n.childIndex(path[0])
+		if i < 0 {
+			child := &node{path: path}
+			n.indices = append(n.indices, path[0])
+			n.children = append(n.children, child)
+			return child
+		}
+		child := n.children[i]
+		l := commonPrefixLen(path, child.path)
+		if l < len(child.path) {
+			split := &node{
+				path:     child.path[:l],
+				indices:  []byte{child.path[l]},
+				children: []*node{child},
+			}
+			child.path = child.path[l:]
+			n.children[i] = split
+			child = split
+		}
+		path = path[l:]
+		n = child
+	}
+	return n
+}
+
+func (n *node) childIndex(b byte) int {
+	for i := range n.indices {
+		if n.indices[i] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// lookup finds the route matching path below n whose
+// matchers match the request. The values of matched params
+// are appended to ps in the order they appear in the route.
+// Routes are tried from most to least specific, regardless
+// of the order in which they were registered: static
+// children first, then regexp routes, then the param child,
+// then regexp routes that can match across segments, and
+// then catch-all routes. A nil request only matches routes
+// without matchers.
+func (n *node) lookup(path string, ps params, r *http.Request) (*route, params) {
+	if path == "" {
+		if rt := firstMatch(n.routes, r); rt != nil {
+			return rt, ps
+		}
+	}
+	if path != "" {
+		if i := n.childIndex(path[0]); i >= 0 {
+			child := n.children[i]
+			if strings.HasPrefix(path, child.path) {
+				if rt, found := child.lookup(path[len(child.path):], ps, r); rt != nil {
+					return rt, found
+				}
+			}
+		}
+	}
+	if rt, found := lookupRegexps(n.regexps, path, ps, r); rt != nil {
+		return rt, found
+	}
+	if n.param != nil {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if rt, found := n.param.lookup(path[end:], append(ps, param{value: path[:end]}), r); rt != nil {
+			return rt, found
+		}
+	}
+	if rt, found := lookupRegexps(n.spanRegexps, path, ps, r); rt != nil {
+		return rt, found
+	}
+	if rt := firstMatch(n.catchAlls, r); rt != nil {
+		return rt, append(ps, param{value: path})
+	}
+	return nil, ps
+}
+
+// lookupFold is like lookup, but matches static text
+// case-insensitively. The path matched, with static text
+// cased as in the route's pattern, is appended to buf.
+func (n *node) lookupFold(path string, buf []byte, r *http.Request) (*route, []byte) {
+	if path == "" {
+		if rt := firstMatch(n.routes, r); rt != nil {
+			return rt, buf
+		}
+	}
+	for _, child := range n.children {
+		if len(path) >= len(child.path) && strings.EqualFold(path[:len(child.path)], child.path) {
+			if rt, found := child.lookupFold(path[len(child.path):], append(buf, child.path...), r); rt != nil {
+				return rt, found
+			}
+		}
+	}
+	if rt, found := lookupRegexpsFold(n.regexps, path, buf, r); rt != nil {
+		return rt, found
+	}
+	if n.param != nil {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if rt, found := n.param.lookupFold(path[end:], append(buf, path[:end]...), r); rt != nil {
+			return rt, found
+		}
+	}
+	if rt, found := lookupRegexpsFold(n.spanRegexps, path, buf, r); rt != nil {
+		return rt, found
+	}
+	if rt := firstMatch(n.catchAlls, r); rt != nil {
+		return rt, append(buf, path...)
+	}
+	return nil, buf
+}
+
+func firstMatch(routes []*route, r *http.Request) *route {
+	for _, rt := range routes {
+		if rt.matches(r) {
+			return rt
+		}
+	}
+	return nil
+}
+
+func lookupRegexps(routes []*route, path string, ps params, r *http.Request) (*route, params) {
+	for _, rt := range routes {
+		if !rt.matches(r) {
+			continue
+		}
+		if matches := rt.lastPart().regexp.FindStringSubmatch(path); matches != nil {
+			for _, m := range matches[1:] {
+				ps = append(ps, param{value: m})
+			}
+			return rt, ps
+		}
+	}
+	return nil, ps
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+func lookupRegexpsFold(routes []*route, path string, buf []byte, r *http.Request) (*route, []byte) {
+	for _, rt := range routes {
+		if !rt.matches(r) {
+			continue
+		}
+		p := rt.lastPart()
+		matches := p.foldRegexp.FindStringSubmatchIndex(path)
+		if matches == nil {
+			continue
+		}
+		i := 2
+		for _, t := range p.tokens {
+			if !t.isParam() {
+				buf = append(buf, t.literal...)
+				continue
+			}
+			buf = append(buf, path[matches[i]:matches[i+1]]...)
+			i += 2
+		}
+		return rt, buf
+	}
+	return nil, buf
+}
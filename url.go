@@ -0,0 +1,125 @@
+package goweb
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Route is a handle to a registered route.
+type Route struct {
+	route  *route
+	engine *Engine
+}
+
+// Name sets the name of the route, so that its URL can be
+// built with Engine.URL. It panics if another route already
+// has the given name.
+func (r *Route) Name(name string) *Route {
+	return r.update(func(named *route) {
+		for _, rt := range r.engine.routes {
+			if rt.name == name {
+				panic("route name '" + name + "' is already in use")
+			}
+		}
+		named.name = name
+		named.reverse = reverseParts(named.parsed)
+	})
+}
+
+// update replaces the route with a copy modified by f, as
+// routes are never modified once they are registered. f is
+// called with the registry locked.
+func (r *Route) update(f func(rt *route)) *Route {
+	e := r.engine
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	updated := *r.route
+	f(&updated)
+	routes := make([]*route, len(e.routes))
+	for i, rt := range e.routes {
+		if rt == r.route {
+			rt = &updated
+		}
+		routes[i] = rt
+	}
+	e.routes = routes
+	e.invalidate()
+	r.route = &updated
+	return r
+}
+
+// URL builds the path of the route with the given name.
+// Params are given as name value pairs. Each value must
+// match the param's constraint, and is escaped before it
+// is put in the path.
+func (e *Engine) URL(name string, pairs ...string) (string, error) {
+	rt := e.load().names[name]
+	if rt == nil {
+		return "", fmt.Errorf("route '%s' not found", name)
+	}
+	return rt.url(pairs)
+}
+
+// reversePart is a static part of a route pattern, or a
+// param with the regexp its values must match.
+type reversePart struct {
+	static string
+	param  string
+	regexp *regexp.Regexp
+}
+
+func reverseParts(pattern *Pattern) []reversePart {
+	var parts []reversePart
+	for _, t := range pattern.tokens {
+		if !t.isParam() {
+			parts = append(parts, reversePart{static: t.literal})
+			continue
+		}
+		parts = append(parts, reversePart{
+			param:  t.name,
+			regexp: regexp.MustCompile("^(?:" + t.paramRegexp() + ")$"),
+		})
+	}
+	return parts
+}
+
+func (rt *route) url(pairs []string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("route '%s' given an odd number of param pairs", rt.name)
+	}
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		if !contains(rt.paramNames, pairs[i]) {
+			return "", fmt.Errorf("route '%s' has no param '%s'", rt.name, pairs[i])
+		}
+		values[pairs[i]] = pairs[i+1]
+	}
+	var b strings.Builder
+	for _, p := range rt.reverse {
+		if p.param == "" {
+			b.WriteString(p.static)
+			continue
+		}
+		value, ok := values[p.param]
+		if !ok {
+			return "", fmt.Errorf("route '%s' missing param '%s'", rt.name, p.param)
+		}
+		if !p.regexp.MatchString(value) {
+			return "", fmt.Errorf("route '%s' param '%s' value '%s' does not match '%s'", rt.name, p.param, value, p.regexp)
+		}
+		b.WriteString(escapePath(value))
+	}
+	return b.String(), nil
+}
+
+// escapePath escapes each segment of the given path, and
+// keeps the slashes between them.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
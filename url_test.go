@@ -0,0 +1,65 @@
+package goweb_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func nopHandler(c *goweb.Context) goweb.Responder {
+	return c.Empty(http.StatusOK)
+}
+
+func assertURL(t *testing.T, app *goweb.Engine, name string, pairs []string, want string) {
+	got, err := app.URL(name, pairs...)
+	if err != nil {
+		t.Errorf("unexpected error building url for '%s': %v", name, err)
+		return
+	}
+	if got != want {
+		t.Errorf("wrong url for '%s': got '%v' want '%v'", name, got, want)
+	}
+}
+
+func assertURLError(t *testing.T, app *goweb.Engine, name string, pairs []string) {
+	if got, err := app.URL(name, pairs...); err == nil {
+		t.Errorf("expected error building url for '%s'; got '%v'", name, got)
+	}
+}
+
+func TestURL(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", nopHandler).Name("home")
+	app.GET("/users/{id:[0-9]+}/posts/{slug}", nopHandler).Name("post")
+	app.GET("/files/{path:.*}", nopHandler).Name("file")
+	assertURL(t, app, "home", nil, "/")
+	assertURL(t, app, "post", []string{"id", "12", "slug", "hello world"}, "/users/12/posts/hello%20world")
+	assertURL(t, app, "post", []string{"slug", "a?b", "id", "3"}, "/users/3/posts/a%3Fb")
+	assertURL(t, app, "file", []string{"path", "css/a b.css"}, "/files/css/a%20b.css")
+}
+
+func TestURLErrors(t *testing.T) {
+	app := goweb.New()
+	app.GET("/users/{id:[0-9]+}/posts/{slug}", nopHandler).Name("post")
+	assertURLError(t, app, "missing", nil)
+	assertURLError(t, app, "post", []string{"id", "12"})
+	assertURLError(t, app, "post", []string{"id", "abc", "slug", "a"})
+	assertURLError(t, app, "post", []string{"id", "1", "slug", "a/b"})
+	assertURLError(t, app, "post", []string{"id", "1", "slug", "a", "foo", "bar"})
+	assertURLError(t, app, "post", []string{"id", "1", "slug"})
+}
+
+func TestURLGroup(t *testing.T) {
+	app := goweb.New()
+	app.Group("/api").Group("/v2").GET("/users/{id}", nopHandler).Name("user")
+	assertURL(t, app, "user", []string{"id", "7"}, "/api/v2/users/7")
+}
+
+func TestDuplicateRouteName(t *testing.T) {
+	app := goweb.New()
+	app.GET("/a", nopHandler).Name("a")
+	assertPanic(t, func() {
+		app.GET("/b", nopHandler).Name("a")
+	})
+}
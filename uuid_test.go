@@ -0,0 +1,49 @@
+package goweb_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+func TestParseUUID(t *testing.T) {
+	u, err := goweb.ParseUUID("123E4567-e89b-12d3-a456-426614174000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "123e4567-e89b-12d3-a456-426614174000"; u.String() != want {
+		t.Fatalf("expected %s; got %s", want, u)
+	}
+	for _, s := range []string{
+		"",
+		"123e4567e89b12d3a456426614174000",
+		"123e4567-e89b-12d3-a456-42661417400g",
+		"123e4567-e89b-12d3-a456_426614174000",
+		"123e4567-e89b-12d3-a456-4266141740000",
+	} {
+		if _, err := goweb.ParseUUID(s); err == nil {
+			t.Fatalf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestUUIDJSON(t *testing.T) {
+	var v struct {
+		ID goweb.UUID `json:"id"`
+	}
+	in := `{"id":"123e4567-e89b-12d3-a456-426614174000"}`
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != in {
+		t.Fatalf("expected %s; got %s", in, out)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"nope"}`), &v); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,415 @@
+package goweb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidationRule checks a field value for a rule of a
+// validate tag. It is given the field, dereferenced if it
+// is a non-nil pointer, and the rule's param, which is the
+// text after "=" in the tag, and returns an error describing
+// the failure, such as "must be even".
+type ValidationRule func(field reflect.Value, param string) error
+
+// FieldError is a field that failed a validation rule. Field
+// is the path of the field, such as "items[0].name", using
+// the names of the fields' json, form, query, header or path
+// tags if they have one.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError is returned by Context.ValidateStruct
+// and Context.Bind if any field fails its validation rules.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	errs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		errs[i] = fe.Error()
+	}
+	return "validation failed: " + strings.Join(errs, "; ")
+}
+
+// RegisterValidation registers a rule for validate tags. A
+// rule with the name of a built-in rule other than required
+// and omitempty replaces it. Rules must be registered before
+// requests are served. It panics if the name is empty,
+// contains ',' or '=', or is required or omitempty.
+func (e *Engine) RegisterValidation(name string, rule ValidationRule) {
+	if name == "" || strings.ContainsAny(name, ",=") || name == "required" || name == "omitempty" {
+		panic("invalid validation rule name '" + name + "'")
+	}
+	if e.validationRules == nil {
+		e.validationRules = make(map[string]ValidationRule)
+	}
+	e.validationRules[name] = rule
+}
+
+// ValidateStruct checks the fields of the given struct, or
+// pointer to a struct, against the rules in their validate
+// tags:
+//
+//	type Input struct {
+//		Name  string   `json:"name" validate:"required,max=64"`
+//		Email string   `json:"email" validate:"omitempty,email"`
+//		Role  string   `json:"role" validate:"oneof=admin user"`
+//		Tags  []string `json:"tags" validate:"max=10"`
+//	}
+//
+// The built-in rules are required (not the zero value),
+// omitempty (skip the other rules if zero), min, max and len
+// (the value of numbers, and the length of strings, slices
+// and maps), oneof (one of the space-separated values) and
+// email. Rules registered with Engine.RegisterValidation are
+// also available. Nested structs, and structs in slices, are
+// validated too. A *ValidationError listing every failed
+// field is returned. Another error is returned if a tag
+// uses an unknown rule, or a built-in rule with a type or
+// param it doesn't support.
+func (c *Context) ValidateStruct(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate struct: target must be a struct or a pointer to a struct, not %T", v)
+	}
+	vd := validator{}
+	if c.engine != nil {
+		vd.rules = c.engine.validationRules
+	}
+	if err := vd.validateStruct(rv, ""); err != nil {
+		return err
+	}
+	if len(vd.errs) > 0 {
+		return &ValidationError{Errors: vd.errs}
+	}
+	return nil
+}
+
+// ValidationFailed responds with status 422 and the failed
+// fields as JSON:
+//
+//	{"errors":[{"field":"name","rule":"required","message":"is required"}]}
+func (c *Context) ValidationFailed(err *ValidationError) Responder {
+	return c.JSON(http.StatusUnprocessableEntity, err)
+}
+
+type validator struct {
+	rules map[string]ValidationRule
+	errs  []FieldError
+}
+
+// rule returns the rule of the tag, preferring a registered
+// rule to a built-in one.
+func (vd *validator) rule(r tagRule) (ValidationRule, error) {
+	if rule, ok := vd.rules[r.name]; ok {
+		return rule, nil
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if rule, ok := builtinRules[r.name]; ok {
+		return rule.rule, nil
+	}
+	return nil, fmt.Errorf("unknown rule '%s'", r.name)
+}
+
+func (vd *validator) validateStruct(v reflect.Value, prefix string) error {
+	for _, f := range validateFieldsOf(v.Type()) {
+		fv := v.Field(f.index)
+		if f.embedded {
+			if err := vd.validateStruct(fv, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+		name := prefix + f.name
+		if err := vd.validateField(fv, name, f.rules); err != nil {
+			return fmt.Errorf("validate struct: field %s of %s: %v", f.goName, v.Type(), err)
+		}
+		if err := vd.validateNested(fv, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vd *validator) validateField(v reflect.Value, name string, rules []tagRule) error {
+	for _, r := range rules {
+		switch r.name {
+		case "omitempty":
+			if v.IsZero() {
+				return nil
+			}
+			continue
+		case "required":
+			if v.IsZero() {
+				vd.errs = append(vd.errs, FieldError{Field: name, Rule: r.name, Message: "is required"})
+				return nil
+			}
+			continue
+		}
+		rule, err := vd.rule(r)
+		if err != nil {
+			return err
+		}
+		field := v
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		if err := rule(field, r.param); err != nil {
+			vd.errs = append(vd.errs, FieldError{Field: name, Rule: r.name, Param: r.param, Message: err.Error()})
+		}
+	}
+	return nil
+}
+
+// validateNested validates structs in the field, if it is a
+// struct, a pointer to one, or a slice or array of them.
+func (vd *validator) validateNested(v reflect.Value, name string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return vd.validateNested(v.Elem(), name)
+		}
+	case reflect.Struct:
+		return vd.validateStruct(v, name+".")
+	case reflect.Slice, reflect.Array:
+		if k := v.Type().Elem().Kind(); k != reflect.Struct && k != reflect.Ptr {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := vd.validateNested(v.Index(i), name+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tagRule is a rule of a validate tag. err is set if the
+// rule is a built-in rule that can't be used on the field.
+type tagRule struct {
+	name  string
+	param string
+	err   error
+}
+
+type validateField struct {
+	index    int
+	name     string
+	goName   string
+	embedded bool
+	rules    []tagRule
+}
+
+var validateFieldCache sync.Map
+
+// validateFieldsOf returns the exported fields of the struct
+// type with their parsed validate tags. The built-in rules
+// of the tags are checked against the fields' types.
+func validateFieldsOf(t reflect.Type) []validateField {
+	if fields, ok := validateFieldCache.Load(t); ok {
+		return fields.([]validateField)
+	}
+	var fields []validateField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, validateField{index: i, embedded: true})
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		f := validateField{index: i, name: fieldName(sf), goName: sf.Name}
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			for _, rule := range strings.Split(tag, ",") {
+				name, param, _ := strings.Cut(rule, "=")
+				r := tagRule{name: name, param: param}
+				if b, ok := builtinRules[name]; ok {
+					if err := b.check(ft, param); err != nil {
+						r.err = fmt.Errorf("rule '%s': %v", name, err)
+					}
+				}
+				f.rules = append(f.rules, r)
+			}
+		}
+		fields = append(fields, f)
+	}
+	validateFieldCache.Store(t, fields)
+	return fields
+}
+
+// fieldName returns the name of the field in its json tag or
+// bind source tag, or its Go name if it has none.
+func fieldName(sf reflect.StructField) string {
+	for _, key := range [...]string{"json", "form", "query", "header", "path"} {
+		name, _, _ := strings.Cut(sf.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// builtinRule is a built-in validation rule. check returns
+// an error if the rule can't be used on fields of the given
+// type with the given param. It is run when a struct type is
+// first validated.
+type builtinRule struct {
+	check func(t reflect.Type, param string) error
+	rule  ValidationRule
+}
+
+var builtinRules = map[string]builtinRule{
+	"min":   {checkMeasure(false), minRule},
+	"max":   {checkMeasure(false), maxRule},
+	"len":   {checkMeasure(true), lenRule},
+	"oneof": {checkOneOf, oneOfRule},
+	"email": {checkEmail, emailRule},
+}
+
+func minRule(v reflect.Value, param string) error {
+	limit, _ := strconv.ParseFloat(param, 64)
+	if n, unit := measure(v); n < limit {
+		return fmt.Errorf("must %s at least %s%s", unitVerb(unit), param, unit)
+	}
+	return nil
+}
+
+func maxRule(v reflect.Value, param string) error {
+	limit, _ := strconv.ParseFloat(param, 64)
+	if n, unit := measure(v); n > limit {
+		return fmt.Errorf("must %s at most %s%s", unitVerb(unit), param, unit)
+	}
+	return nil
+}
+
+func lenRule(v reflect.Value, param string) error {
+	limit, _ := strconv.ParseFloat(param, 64)
+	if n, unit := measure(v); n != limit {
+		return fmt.Errorf("must %s exactly %s%s", unitVerb(unit), param, unit)
+	}
+	return nil
+}
+
+// checkMeasure returns the check of a rule comparing a
+// number, or the length of a string, slice or map, with its
+// param. Numbers are not supported if lengthOnly is set.
+func checkMeasure(lengthOnly bool) func(reflect.Type, string) error {
+	return func(t reflect.Type, param string) error {
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return fmt.Errorf("invalid param '%s'", param)
+		}
+		unit, ok := measureUnit(t.Kind())
+		if !ok || lengthOnly && unit == "" {
+			return fmt.Errorf("type %s is not supported", t)
+		}
+		return nil
+	}
+}
+
+// measureUnit returns the unit in which values of the kind
+// are measured, which is empty for numbers.
+func measureUnit(kind reflect.Kind) (string, bool) {
+	switch kind {
+	case reflect.String:
+		return " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "", true
+	}
+	return "", false
+}
+
+// measure returns the value of a number, or the length of a
+// string, slice or map with the unit of the length.
+func measure(v reflect.Value) (float64, string) {
+	unit, _ := measureUnit(v.Kind())
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), unit
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), unit
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), unit
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), unit
+	}
+	return v.Float(), unit
+}
+
+func unitVerb(unit string) string {
+	if unit == " items" {
+		return "have"
+	}
+	return "be"
+}
+
+func checkOneOf(t reflect.Type, _ string) error {
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return nil
+	}
+	return fmt.Errorf("type %s is not supported", t)
+}
+
+func oneOfRule(v reflect.Value, param string) error {
+	s := fmt.Sprint(v.Interface())
+	if v.Kind() == reflect.String {
+		s = v.String()
+	}
+	options := strings.Fields(param)
+	for _, option := range options {
+		if s == option {
+			return nil
+		}
+	}
+	return errors.New("must be one of " + strings.Join(options, ", "))
+}
+
+func checkEmail(t reflect.Type, _ string) error {
+	if t.Kind() != reflect.String {
+		return fmt.Errorf("type %s is not supported", t)
+	}
+	return nil
+}
+
+func emailRule(v reflect.Value, _ string) error {
+	addr, err := mail.ParseAddress(v.String())
+	if err != nil || addr.Name != "" || addr.Address != v.String() {
+		return errors.New("must be a valid email address")
+	}
+	return nil
+}
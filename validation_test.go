@@ -0,0 +1,195 @@
+package goweb_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/twharmon/goweb"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type validateItem struct {
+	SKU      string `json:"sku" validate:"len=4"`
+	Quantity int    `json:"quantity" validate:"min=1,max=10"`
+}
+
+type validateInput struct {
+	Name    string           `json:"name" validate:"required,min=2,max=8"`
+	Email   string           `json:"email" validate:"omitempty,email"`
+	Role    string           `json:"role" validate:"oneof=admin user"`
+	Age     *int             `json:"age" validate:"min=18"`
+	Tags    []string         `json:"tags" validate:"max=2"`
+	Page    int              `query:"page" validate:"min=1"`
+	Address *validateAddress `json:"address"`
+	Items   []validateItem   `json:"items"`
+}
+
+func validateHandler(c *goweb.Context) goweb.Responder {
+	var in validateInput
+	err := c.Bind(&in)
+	var ve *goweb.ValidationError
+	if errors.As(err, &ve) {
+		return c.ValidationFailed(ve)
+	}
+	if err != nil {
+		return c.Text(http.StatusBadRequest, err.Error())
+	}
+	return c.Text(http.StatusOK, "ok")
+}
+
+func withJSON(r *http.Request) {
+	r.Header.Set("Content-Type", "application/json")
+}
+
+func TestValidate(t *testing.T) {
+	app := goweb.New()
+	app.POST("/", validateHandler)
+	body := `{"name":"gopher","role":"admin","age":30,"tags":["a"],"address":{"city":"Oslo"},"items":[{"sku":"abcd","quantity":2}]}`
+	assert(t, app, "POST", "/?page=1", strings.NewReader(body), withJSON, http.StatusOK, "ok")
+}
+
+func TestValidateErrors(t *testing.T) {
+	app := goweb.New()
+	app.POST("/", validateHandler)
+	body := `{"name":"g","email":"nope","role":"root","age":12,"tags":["a","b","c"],"address":{},"items":[{"sku":"abc","quantity":0}]}`
+	want := `{"errors":[` +
+		`{"field":"name","rule":"min","param":"2","message":"must be at least 2 characters"},` +
+		`{"field":"email","rule":"email","message":"must be a valid email address"},` +
+		`{"field":"role","rule":"oneof","param":"admin user","message":"must be one of admin, user"},` +
+		`{"field":"age","rule":"min","param":"18","message":"must be at least 18"},` +
+		`{"field":"tags","rule":"max","param":"2","message":"must have at most 2 items"},` +
+		`{"field":"page","rule":"min","param":"1","message":"must be at least 1"},` +
+		`{"field":"address.city","rule":"required","message":"is required"},` +
+		`{"field":"items[0].sku","rule":"len","param":"4","message":"must be exactly 4 characters"},` +
+		`{"field":"items[0].quantity","rule":"min","param":"1","message":"must be at least 1"}` +
+		"]}\n"
+	assert(t, app, "POST", "/", strings.NewReader(body), withJSON, http.StatusUnprocessableEntity, want)
+}
+
+func TestValidateRequired(t *testing.T) {
+	app := goweb.New()
+	app.POST("/", validateHandler)
+	want := `{"errors":[{"field":"name","rule":"required","message":"is required"},` +
+		`{"field":"role","rule":"oneof","param":"admin user","message":"must be one of admin, user"},` +
+		`{"field":"page","rule":"min","param":"1","message":"must be at least 1"}]}` + "\n"
+	assert(t, app, "POST", "/", nil, nil, http.StatusUnprocessableEntity, want)
+}
+
+func TestRegisterValidation(t *testing.T) {
+	type input struct {
+		N int `query:"n" validate:"even"`
+	}
+	app := goweb.New()
+	app.RegisterValidation("even", func(field reflect.Value, _ string) error {
+		if field.Int()%2 != 0 {
+			return errors.New("must be even")
+		}
+		return nil
+	})
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		var in input
+		if err := c.Bind(&in); err != nil {
+			return c.Text(http.StatusUnprocessableEntity, err.Error())
+		}
+		return c.Text(http.StatusOK, fmt.Sprint(in.N))
+	})
+	assert(t, app, "GET", "/?n=2", nil, nil, http.StatusOK, "2")
+	assert(t, app, "GET", "/?n=3", nil, nil, http.StatusUnprocessableEntity, "validation failed: n must be even")
+}
+
+func TestRegisterValidationPanic(t *testing.T) {
+	app := goweb.New()
+	rule := func(reflect.Value, string) error { return nil }
+	assertPanic(t, func() { app.RegisterValidation("", rule) })
+	assertPanic(t, func() { app.RegisterValidation("a,b", rule) })
+	assertPanic(t, func() { app.RegisterValidation("required", rule) })
+}
+
+func TestValidateUnknownRule(t *testing.T) {
+	type input struct {
+		Name string `validate:"requird"`
+	}
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		var in input
+		if err := c.Bind(&in); err != nil {
+			return c.Text(http.StatusInternalServerError, err.Error())
+		}
+		return c.Text(http.StatusOK, "")
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusInternalServerError,
+		"validate struct: field Name of goweb_test.input: unknown rule 'requird'")
+}
+
+func TestValidateUnsupportedRule(t *testing.T) {
+	type input struct {
+		Admin bool `validate:"min=1"`
+	}
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		err := c.ValidateStruct(&input{})
+		var ve *goweb.ValidationError
+		if err == nil || errors.As(err, &ve) {
+			return c.Text(http.StatusOK, "expected a tag error")
+		}
+		return c.Text(http.StatusInternalServerError, err.Error())
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusInternalServerError,
+		"validate struct: field Admin of goweb_test.input: rule 'min': type bool is not supported")
+}
+
+func TestValidateTagErrors(t *testing.T) {
+	tests := map[string]interface{}{
+		"rule 'len': type int is not supported": &struct {
+			Age int `validate:"len=2"`
+		}{},
+		"rule 'max': invalid param 'x'": &struct {
+			Code *string `validate:"max=x"`
+		}{Code: new(string)},
+		"rule 'oneof': type float64 is not supported": &struct {
+			Role float64 `validate:"oneof=1 2"`
+		}{},
+		"rule 'email': type int is not supported": &struct {
+			Email int `validate:"email"`
+		}{Email: 1},
+	}
+	app := goweb.New()
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		for want, v := range tests {
+			if err := c.ValidateStruct(v); err == nil || !strings.HasSuffix(err.Error(), want) {
+				return c.Text(http.StatusInternalServerError, fmt.Sprintf("expected %q; got %v", want, err))
+			}
+		}
+		return c.Text(http.StatusOK, "")
+	})
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "")
+}
+
+func TestRegisteredRuleReplacesBuiltin(t *testing.T) {
+	type input struct {
+		Admin bool `query:"admin" validate:"min"`
+	}
+	app := goweb.New()
+	app.RegisterValidation("min", func(field reflect.Value, _ string) error {
+		if !field.Bool() {
+			return errors.New("must be set")
+		}
+		return nil
+	})
+	app.GET("/", func(c *goweb.Context) goweb.Responder {
+		var in input
+		if err := c.Bind(&in); err != nil {
+			return c.Text(http.StatusUnprocessableEntity, err.Error())
+		}
+		return c.Text(http.StatusOK, "")
+	})
+	assert(t, app, "GET", "/?admin=true", nil, nil, http.StatusOK, "")
+	assert(t, app, "GET", "/", nil, nil, http.StatusUnprocessableEntity, "validation failed: admin must be set")
+}
@@ -0,0 +1,170 @@
+package goweb
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Versioning sets how the API version of a request is read.
+// The version is read from the header first, then from the
+// Accept header, and is Default if neither has one. A
+// leading "v" is ignored, so "v2" and "2" are the same
+// version.
+type Versioning struct {
+	// Header is the name of a request header holding the
+	// version, such as "Api-Version".
+	Header string
+
+	// MediaType is the vendor media type whose versioned
+	// variants in the Accept header hold the version. With
+	// "application/vnd.acme", a request that accepts
+	// "application/vnd.acme.v2+json" has version "2".
+	MediaType string
+
+	// Default is the version of requests without one.
+	Default string
+
+	// Policies are the lifecycle policies of versions.
+	Policies map[string]VersionPolicy
+}
+
+// VersionPolicy describes the lifecycle of an API version.
+// Its headers are added to responses from routes for the
+// version.
+type VersionPolicy struct {
+	// Deprecated adds a "Deprecation: true" header.
+	Deprecated bool
+
+	// Sunset adds a Sunset header with the time after which
+	// the version may stop working.
+	Sunset time.Time
+
+	// Link adds a Link header pointing to documentation of
+	// the deprecation.
+	Link string
+}
+
+// Versioning sets how the API version of requests is read.
+// Routes are declared for a version with Route.Version or
+// Group.Version.
+func (e *Engine) Versioning(versioning Versioning) {
+	e.versioning = versioning
+}
+
+// Version adds a matcher so that the route only matches
+// requests for the given API version. Routes for other
+// versions, or without a version, with the same pattern are
+// tried if the version doesn't match. See Engine.Versioning.
+func (r *Route) Version(version string) *Route {
+	return r.update(func(rt *route) {
+		setVersion(r.engine, rt, version)
+	})
+}
+
+// Version returns a new group whose routes only match
+// requests for the given API version. See Route.Version.
+func (g *Group) Version(version string) *Group {
+	v := *g
+	v.version = version
+	return &v
+}
+
+// versionMatcherPrefix starts the key of version matchers.
+const versionMatcherPrefix = "version "
+
+// setVersion sets the version of the route, replacing the
+// matcher of any version it already has.
+func setVersion(e *Engine, rt *route, version string) {
+	version = normalizeVersion(version)
+	rt.version = version
+	matchers := make([]matcher, 0, len(rt.matchers)+1)
+	for _, m := range rt.matchers {
+		if !strings.HasPrefix(m.key, versionMatcherPrefix) {
+			matchers = append(matchers, m)
+		}
+	}
+	rt.matchers = append(matchers, matcher{
+		key: versionMatcherPrefix + version,
+		match: func(r *http.Request) bool {
+			return e.versioning.version(r) == version
+		},
+	})
+}
+
+// version returns the API version of the request.
+func (v *Versioning) version(r *http.Request) string {
+	if v.Header != "" {
+		if version := r.Header.Get(v.Header); version != "" {
+			return normalizeVersion(version)
+		}
+	}
+	if v.MediaType != "" {
+		if version := mediaTypeVersion(r.Header.Values("Accept"), v.MediaType); version != "" {
+			return version
+		}
+	}
+	return normalizeVersion(v.Default)
+}
+
+// mediaTypeVersion returns the version of the first
+// versioned variant of the media type in the Accept header
+// values.
+func mediaTypeVersion(accept []string, mediaType string) string {
+	prefix := strings.ToLower(mediaType) + ".v"
+	for _, value := range accept {
+		for _, mediaRange := range strings.Split(value, ",") {
+			if i := strings.IndexByte(mediaRange, ';'); i >= 0 {
+				mediaRange = mediaRange[:i]
+			}
+			mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+			if !strings.HasPrefix(mediaRange, prefix) {
+				continue
+			}
+			version := mediaRange[len(prefix):]
+			if i := strings.IndexByte(version, '+'); i >= 0 {
+				version = version[:i]
+			}
+			if version != "" {
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') {
+		return version[1:]
+	}
+	return version
+}
+
+// setPolicyHeaders adds the headers of the policy of the
+// given version.
+func (v *Versioning) setPolicyHeaders(h http.Header, version string) {
+	for key, policy := range v.Policies {
+		if normalizeVersion(key) == version {
+			policy.setHeaders(h)
+		}
+	}
+}
+
+func (policy *VersionPolicy) setHeaders(h http.Header) {
+	if policy.Deprecated {
+		h.Set("Deprecation", "true")
+	}
+	if !policy.Sunset.IsZero() {
+		h.Set("Sunset", policy.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if policy.Link != "" {
+		h.Add("Link", "<"+policy.Link+">; rel=\"deprecation\"")
+	}
+}
+
+// Version returns the API version of the request. See
+// Engine.Versioning.
+func (c *Context) Version() string {
+	return c.engine.versioning.version(c.Request)
+}
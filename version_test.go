@@ -0,0 +1,99 @@
+package goweb_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/twharmon/goweb"
+)
+
+func withHeader(key string, value string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set(key, value)
+	}
+}
+
+func versionHandler(c *goweb.Context) goweb.Responder {
+	return c.Text(http.StatusOK, c.Version())
+}
+
+func TestVersionHeader(t *testing.T) {
+	app := goweb.New()
+	app.Versioning(goweb.Versioning{Header: "Api-Version", Default: "1"})
+	app.GET("/orders", textHandler("v1")).Version("1")
+	app.GET("/orders", textHandler("v2")).Version("v2")
+	assert(t, app, "GET", "/orders", nil, nil, http.StatusOK, "v1")
+	assert(t, app, "GET", "/orders", nil, withHeader("Api-Version", "2"), http.StatusOK, "v2")
+	assert(t, app, "GET", "/orders", nil, withHeader("Api-Version", "v2"), http.StatusOK, "v2")
+	assert(t, app, "GET", "/orders", nil, withHeader("Api-Version", "3"), http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+}
+
+func TestVersionMediaType(t *testing.T) {
+	app := goweb.New()
+	app.Versioning(goweb.Versioning{MediaType: "application/vnd.acme", Default: "1"})
+	app.GET("/orders", textHandler("v1")).Version("1")
+	app.GET("/orders", textHandler("v2")).Version("2")
+	assert(t, app, "GET", "/orders", nil, withHeader("Accept", "application/vnd.acme.v2+json"), http.StatusOK, "v2")
+	assert(t, app, "GET", "/orders", nil, withHeader("Accept", "text/html, application/vnd.acme.v1+json;q=0.9"), http.StatusOK, "v1")
+	assert(t, app, "GET", "/orders", nil, withHeader("Accept", "application/json"), http.StatusOK, "v1")
+}
+
+func TestVersionFallback(t *testing.T) {
+	app := goweb.New()
+	app.Versioning(goweb.Versioning{Header: "Api-Version"})
+	app.GET("/orders", textHandler("v2")).Version("2")
+	app.GET("/orders", textHandler("unversioned"))
+	assert(t, app, "GET", "/orders", nil, withHeader("Api-Version", "2"), http.StatusOK, "v2")
+	assert(t, app, "GET", "/orders", nil, withHeader("Api-Version", "3"), http.StatusOK, "unversioned")
+	assert(t, app, "GET", "/orders", nil, nil, http.StatusOK, "unversioned")
+}
+
+func TestVersionReplaced(t *testing.T) {
+	app := goweb.New()
+	app.Versioning(goweb.Versioning{Header: "Api-Version", Default: "1"})
+	app.GET("/orders", versionHandler).Version("1").Version("2")
+	app.Group("/api").Version("1").GET("/orders", versionHandler).Version("3")
+	assert(t, app, "GET", "/orders", nil, withHeader("Api-Version", "2"), http.StatusOK, "2")
+	assert(t, app, "GET", "/orders", nil, nil, http.StatusNotFound, "{\"message\":\"Page Not Found\"}")
+	assert(t, app, "GET", "/api/orders", nil, withHeader("Api-Version", "3"), http.StatusOK, "3")
+}
+
+func TestGroupVersion(t *testing.T) {
+	app := goweb.New()
+	app.Versioning(goweb.Versioning{Header: "Api-Version", Default: "1"})
+	api := app.Group("/api")
+	api.Version("1").GET("/orders", versionHandler)
+	api.Version("2").Group("/", appendMiddleware("a")).GET("/orders", traceHandler)
+	assert(t, app, "GET", "/api/orders", nil, nil, http.StatusOK, "1")
+	assert(t, app, "GET", "/api/orders", nil, withHeader("Api-Version", "2"), http.StatusOK, "a")
+	if err := app.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVersionPolicy(t *testing.T) {
+	sunset := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	app := goweb.New()
+	app.Versioning(goweb.Versioning{
+		Header:  "Api-Version",
+		Default: "1",
+		Policies: map[string]goweb.VersionPolicy{
+			"v1": {Deprecated: true, Sunset: sunset, Link: "https://example.com/migrate"},
+		},
+	})
+	app.GET("/orders", versionHandler).Version("1")
+	app.GET("/orders", versionHandler).Version("2")
+	assertHeader(t, app, "GET", "/orders", "Deprecation", "true")
+	assertHeader(t, app, "GET", "/orders", "Sunset", "Tue, 01 Jan 2030 00:00:00 GMT")
+	assertHeader(t, app, "GET", "/orders", "Link", "<https://example.com/migrate>; rel=\"deprecation\"")
+	assert(t, app, "GET", "/orders", nil, withHeader("Api-Version", "2"), http.StatusOK, "2")
+}
+
+func TestContextVersion(t *testing.T) {
+	app := goweb.New()
+	app.GET("/", versionHandler)
+	assert(t, app, "GET", "/", nil, nil, http.StatusOK, "")
+	app.Versioning(goweb.Versioning{Header: "Api-Version", Default: "1"})
+	assert(t, app, "GET", "/", nil, withHeader("Api-Version", "V3"), http.StatusOK, "3")
+}